@@ -0,0 +1,77 @@
+package corint
+
+/*
+#include <stdlib.h>
+
+char* corint_abi_check();
+void corint_string_free(char* s);
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// abiVersion is the native ABI version this binding is written against
+const abiVersion = 1
+
+// ErrABIMismatch is returned when the native library's ABI contract differs from the binding's
+var ErrABIMismatch = errors.New("native library ABI mismatch")
+
+// abiContract is the ABI contract reported by corint_abi_check
+type abiContract struct {
+	ABIVersion  int `json:"abi_version"`
+	PointerSize int `json:"pointer_size"`
+	CIntSize    int `json:"c_int_size"`
+}
+
+// nativeABIContract reads the native contract; tests replace it to simulate a mismatch
+var nativeABIContract = func() (abiContract, error) {
+	contractPtr := C.corint_abi_check()
+	if contractPtr == nil {
+		return abiContract{}, errors.New("failed to read native ABI contract")
+	}
+	defer C.corint_string_free(contractPtr)
+
+	var contract abiContract
+	if err := json.Unmarshal([]byte(C.GoString(contractPtr)), &contract); err != nil {
+		return abiContract{}, err
+	}
+	return contract, nil
+}
+
+var (
+	abiOnce sync.Once
+	abiErr  error
+)
+
+// CheckABI verifies once per process that the loaded native library matches
+// the ABI this binding expects. Engine constructors call it before creating
+// a handle, so an incompatible library fails at startup with ErrABIMismatch.
+func CheckABI() error {
+	abiOnce.Do(func() {
+		abiErr = verifyABI()
+	})
+	return abiErr
+}
+
+// verifyABI compares the native contract with the binding's expectations
+func verifyABI() error {
+	native, err := nativeABIContract()
+	if err != nil {
+		return err
+	}
+
+	expected := abiContract{
+		ABIVersion:  abiVersion,
+		PointerSize: int(unsafe.Sizeof(uintptr(0))),
+		CIntSize:    int(C.sizeof_int),
+	}
+	if native != expected {
+		return fmt.Errorf("%w: native library reports %+v, binding expects %+v", ErrABIMismatch, native, expected)
+	}
+	return nil
+}
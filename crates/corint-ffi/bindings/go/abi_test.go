@@ -0,0 +1,66 @@
+package corint
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeABIContract makes CheckABI read the native contract as changed by edit,
+// starting from a fresh check and restoring the real one afterwards
+func fakeABIContract(t *testing.T, edit func(*abiContract)) {
+	t.Helper()
+	original := nativeABIContract
+	nativeABIContract = func() (abiContract, error) {
+		contract, err := original()
+		if err == nil {
+			edit(&contract)
+		}
+		return contract, err
+	}
+	abiOnce, abiErr = sync.Once{}, nil
+	t.Cleanup(func() {
+		nativeABIContract = original
+		abiOnce, abiErr = sync.Once{}, nil
+	})
+}
+
+func TestCheckABIMatches(t *testing.T) {
+	fakeABIContract(t, func(*abiContract) {})
+	if err := CheckABI(); err != nil {
+		t.Fatalf("CheckABI: %v", err)
+	}
+}
+
+func TestCheckABIMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(*abiContract)
+	}{
+		{"abi version", func(c *abiContract) { c.ABIVersion = abiVersion + 1 }},
+		{"pointer size", func(c *abiContract) { c.PointerSize = 4 }},
+		{"int size", func(c *abiContract) { c.CIntSize = 8 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeABIContract(t, tt.edit)
+			if err := CheckABI(); !errors.Is(err, ErrABIMismatch) {
+				t.Fatalf("CheckABI() error = %v, want ErrABIMismatch", err)
+			}
+			if _, err := NewEngine("/nonexistent"); !errors.Is(err, ErrABIMismatch) {
+				t.Errorf("NewEngine() error = %v, want ErrABIMismatch", err)
+			}
+		})
+	}
+}
+
+func TestCheckABIReadFailure(t *testing.T) {
+	errUnreadable := errors.New("failed to read native ABI contract")
+	fakeABIContract(t, nil)
+	nativeABIContract = func() (abiContract, error) { return abiContract{}, errUnreadable }
+
+	if err := CheckABI(); !errors.Is(err, errUnreadable) {
+		t.Errorf("CheckABI() error = %v, want the read failure", err)
+	}
+}
@@ -0,0 +1,97 @@
+package corint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Assertion is an expected outcome for a decision request, loaded from a JSON file:
+//
+//	{
+//	  "name": "new device login is reviewed",
+//	  "request": {"event_data": {...}, "features": {...}},
+//	  "expect": {"decision": "review", "actions": ["OTP"]}
+//	}
+type Assertion struct {
+	Name    string          `json:"name"`
+	Request DecisionRequest `json:"request"`
+	Expect  struct {
+		Decision string   `json:"decision"`
+		Actions  []string `json:"actions,omitempty"`
+	} `json:"expect"`
+}
+
+// AssertionResult is the outcome of running one assertion file
+type AssertionResult struct {
+	File   string
+	Name   string
+	Passed bool
+	// Diffs describes each mismatch between the expected and actual outcome
+	Diffs []string
+	// Err is set when the file could not be parsed or the decision failed
+	Err error
+}
+
+// RunAssertions runs every *.json assertion file in dir against the engine and
+// reports pass/fail for each, in file name order. Expected actions are
+// compared as a set and only checked when listed. The returned error is
+// limited to failures reading dir itself.
+func (e *DecisionEngine) RunAssertions(dir string) ([]AssertionResult, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	results := make([]AssertionResult, 0, len(files))
+	for _, file := range files {
+		results = append(results, e.runAssertionFile(file))
+	}
+	return results, nil
+}
+
+// runAssertionFile loads and runs a single assertion file
+func (e *DecisionEngine) runAssertionFile(file string) AssertionResult {
+	result := AssertionResult{File: file, Name: filepath.Base(file)}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	var assertion Assertion
+	if err := json.Unmarshal(data, &assertion); err != nil {
+		result.Err = fmt.Errorf("parse assertion: %w", err)
+		return result
+	}
+	if assertion.Name != "" {
+		result.Name = assertion.Name
+	}
+
+	response, err := e.Decide(&assertion.Request)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if response.Decision != assertion.Expect.Decision {
+		result.Diffs = append(result.Diffs, fmt.Sprintf("decision: expected %q, got %q", assertion.Expect.Decision, response.Decision))
+	}
+	if assertion.Expect.Actions != nil {
+		if missing := subtractActions(assertion.Expect.Actions, response.Actions); len(missing) > 0 {
+			result.Diffs = append(result.Diffs, fmt.Sprintf("actions: missing %v", missing))
+		}
+		if extra := subtractActions(response.Actions, assertion.Expect.Actions); len(extra) > 0 {
+			result.Diffs = append(result.Diffs, fmt.Sprintf("actions: unexpected %v", extra))
+		}
+	}
+
+	result.Passed = len(result.Diffs) == 0
+	return result
+}
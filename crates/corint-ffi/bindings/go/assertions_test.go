@@ -0,0 +1,57 @@
+package corint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunAssertions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_decline.json": `{"name":"high amount declines","request":{"event_data":{"type":"login"}},"expect":{"decision":"decline","actions":["BLOCK"]}}`,
+		"b_review.json":  `{"name":"expects review","request":{"event_data":{"type":"login"}},"expect":{"decision":"review","actions":["OTP"]}}`,
+		"c_broken.json":  `{"name":`,
+		"notes.txt":      "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := newFakeEngine(t, respondWith(fixtureResponseJSON))
+	results, err := engine.RunAssertions(dir)
+	if err != nil {
+		t.Fatalf("RunAssertions: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want one per JSON file: %+v", len(results), results)
+	}
+
+	if pass := results[0]; !pass.Passed || pass.Name != "high amount declines" || pass.Err != nil {
+		t.Errorf("passing assertion = %+v", pass)
+	}
+
+	fail := results[1]
+	want := []string{`decision: expected "review", got "decline"`, "actions: missing [OTP]", "actions: unexpected [BLOCK]"}
+	if fail.Passed || len(fail.Diffs) != len(want) {
+		t.Fatalf("failing assertion = %+v, want diffs %q", fail, want)
+	}
+	for i := range want {
+		if fail.Diffs[i] != want[i] {
+			t.Errorf("Diffs[%d] = %q, want %q", i, fail.Diffs[i], want[i])
+		}
+	}
+
+	if broken := results[2]; broken.Passed || broken.Err == nil || broken.Name != "c_broken.json" {
+		t.Errorf("unparsable assertion = %+v", broken)
+	}
+}
+
+func TestRunAssertionsMissingDir(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(fixtureResponseJSON))
+	if _, err := engine.RunAssertions(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,153 @@
+package corint
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DenySignal is the signal type of a denying decision
+const DenySignal = "decline"
+
+// AnyDeny evaluates requests concurrently and reports whether any of them is
+// declined, together with the index of the declined request. Remaining
+// requests are not started once a decline is found or ctx is done. If no
+// request is declined, the first decision error (if any) is returned.
+// Engines implementing ContextEngine decide with ctx.
+func AnyDeny(ctx context.Context, e Engine, requests []*DecisionRequest) (bool, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indices := make(chan int)
+	go func() {
+		defer close(indices)
+		for i := range requests {
+			select {
+			case indices <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		mu       sync.Mutex
+		denied   = -1
+		firstErr error
+		errIndex = len(requests)
+		wg       sync.WaitGroup
+	)
+
+	workers := runtime.NumCPU()
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if ctx.Err() != nil {
+					return
+				}
+				response, err := decideContext(ctx, e, requests[i])
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					if i < errIndex {
+						firstErr, errIndex = err, i
+					}
+				case response.Decision == DenySignal && denied < 0:
+					denied = i
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if denied >= 0 {
+		return true, denied, nil
+	}
+	if firstErr != nil {
+		return false, errIndex, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return false, -1, err
+	}
+	return false, -1, nil
+}
+
+// BatchError reports the requests of a batch that failed, keyed by index
+type BatchError struct {
+	errors map[int]error
+}
+
+// Error summarizes the failures, quoting the lowest failed index
+func (e *BatchError) Error() string {
+	failed := e.Failed()
+	if len(failed) == 0 {
+		return "batch failed"
+	}
+	return fmt.Sprintf("%d batch requests failed; request %d: %v", len(failed), failed[0], e.errors[failed[0]])
+}
+
+// Errors returns the error of each failed request keyed by its index
+func (e *BatchError) Errors() map[int]error {
+	return e.errors
+}
+
+// Unwrap returns the failures in index order so errors.Is and errors.As see each of them
+func (e *BatchError) Unwrap() []error {
+	failed := e.Failed()
+	errs := make([]error, len(failed))
+	for i, index := range failed {
+		errs[i] = e.errors[index]
+	}
+	return errs
+}
+
+// Failed returns the indices of the failed requests in ascending order
+func (e *BatchError) Failed() []int {
+	failed := make([]int, 0, len(e.errors))
+	for i := range e.errors {
+		failed = append(failed, i)
+	}
+	sort.Ints(failed)
+	return failed
+}
+
+// DecideBatch executes every request in order. Responses are index-aligned
+// with requests and nil for failed requests; if any request failed the
+// returned error is a *BatchError describing them.
+func DecideBatch(e Engine, requests []*DecisionRequest) ([]*DecisionResponse, error) {
+	return DecideBatchContext(context.Background(), e, requests)
+}
+
+// DecideBatchContext is DecideBatch passing ctx to engines implementing
+// ContextEngine, such as to a DecisionEngine's authorizer
+func DecideBatchContext(ctx context.Context, e Engine, requests []*DecisionRequest) ([]*DecisionResponse, error) {
+	responses := make([]*DecisionResponse, len(requests))
+	var batchErr *BatchError
+
+	for i, request := range requests {
+		response, err := decideContext(ctx, e, request)
+		if err != nil {
+			if batchErr == nil {
+				batchErr = &BatchError{errors: make(map[int]error)}
+			}
+			batchErr.errors[i] = err
+			continue
+		}
+		responses[i] = response
+	}
+
+	if batchErr != nil {
+		return responses, batchErr
+	}
+	return responses, nil
+}
@@ -0,0 +1,156 @@
+package corint
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// indexedRequests returns n requests whose event carries their index
+func indexedRequests(n int) []*DecisionRequest {
+	requests := make([]*DecisionRequest, n)
+	for i := range requests {
+		requests[i] = &DecisionRequest{EventData: map[string]interface{}{"index": i}}
+	}
+	return requests
+}
+
+func TestAnyDenyStopsAtFirstDeny(t *testing.T) {
+	requests := indexedRequests(20 * runtime.NumCPU())
+	var calls atomic.Int64
+	engine := EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+		calls.Add(1)
+		if request.EventData["index"] == 1 {
+			return decided(DenySignal), nil
+		}
+		time.Sleep(5 * time.Millisecond)
+		return decided("approve"), nil
+	})
+
+	denied, index, err := AnyDeny(context.Background(), engine, requests)
+	if err != nil {
+		t.Fatalf("AnyDeny: %v", err)
+	}
+	if !denied || index != 1 {
+		t.Fatalf("AnyDeny() = %v, %d; want true, 1", denied, index)
+	}
+	if n := calls.Load(); n >= int64(len(requests)) {
+		t.Fatalf("all %d requests were decided after an early deny", n)
+	}
+}
+
+func TestAnyDenyNoDeny(t *testing.T) {
+	engine := EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		return decided("approve"), nil
+	})
+
+	denied, index, err := AnyDeny(context.Background(), engine, indexedRequests(10))
+	if err != nil || denied || index != -1 {
+		t.Fatalf("AnyDeny() = %v, %d, %v; want false, -1, nil", denied, index, err)
+	}
+}
+
+func TestAnyDenyReportsFirstError(t *testing.T) {
+	failure := errors.New("native failure")
+	engine := EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+		if i := request.EventData["index"].(int); i == 3 || i == 7 {
+			return nil, failure
+		}
+		return decided("approve"), nil
+	})
+
+	denied, index, err := AnyDeny(context.Background(), engine, indexedRequests(10))
+	if !errors.Is(err, failure) || denied || index != 3 {
+		t.Fatalf("AnyDeny() = %v, %d, %v; want false, 3, native failure", denied, index, err)
+	}
+}
+
+func TestAnyDenyRespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	engine := EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		t.Error("request decided after the context was canceled")
+		return decided("approve"), nil
+	})
+
+	if _, _, err := AnyDeny(ctx, engine, indexedRequests(5)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AnyDeny error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDecideBatchReportsFailuresByIndex(t *testing.T) {
+	failures := map[int]error{
+		1: ErrStaleEvent,
+		4: &DecisionError{Message: "unknown repository: x", Code: "unknown_repository", sentinel: ErrUnknownRepository},
+	}
+	engine := EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+		if err, ok := failures[request.EventData["index"].(int)]; ok {
+			return nil, err
+		}
+		return decided("approve"), nil
+	})
+
+	responses, err := DecideBatch(engine, indexedRequests(6))
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("DecideBatch() error = %v, want a *BatchError", err)
+	}
+	if got := batchErr.Failed(); !reflect.DeepEqual(got, []int{1, 4}) {
+		t.Errorf("Failed() = %v, want [1 4]", got)
+	}
+	if !reflect.DeepEqual(batchErr.Errors(), failures) {
+		t.Errorf("Errors() = %v, want %v", batchErr.Errors(), failures)
+	}
+	if !errors.Is(err, ErrStaleEvent) || !errors.Is(err, ErrUnknownRepository) {
+		t.Error("BatchError does not unwrap to each failure")
+	}
+	if want := "2 batch requests failed; request 1: " + ErrStaleEvent.Error(); err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	for i, response := range responses {
+		_, failed := failures[i]
+		if failed != (response == nil) {
+			t.Errorf("responses[%d] = %v, want nil only for failed requests", i, response)
+		}
+	}
+}
+
+func TestDecideBatchAllSucceed(t *testing.T) {
+	responses, err := DecideBatch(answering("approve"), indexedRequests(3))
+	if err != nil {
+		t.Fatalf("DecideBatch: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Errorf("got %d responses, want 3", len(responses))
+	}
+}
+
+func TestBatchErrorZeroValue(t *testing.T) {
+	var batchErr BatchError
+	if got := batchErr.Error(); got != "batch failed" {
+		t.Errorf("Error() = %q, want batch failed", got)
+	}
+	if len(batchErr.Failed()) != 0 || len(batchErr.Unwrap()) != 0 {
+		t.Errorf("zero BatchError reports failures %v", batchErr.Failed())
+	}
+}
+
+func TestBatchHelpersPassContext(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse), WithAuthorizer(tenantAuthorizer))
+	blocked := context.WithValue(context.Background(), tenantKey{}, "blocked")
+
+	if _, _, err := AnyDeny(blocked, engine, indexedRequests(2)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("AnyDeny() error = %v, want ErrUnauthorized from the context's tenant", err)
+	}
+	if _, err := DecideBatchContext(blocked, engine, indexedRequests(2)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("DecideBatchContext() error = %v, want ErrUnauthorized from the context's tenant", err)
+	}
+	if _, err := DecideBatch(engine, indexedRequests(2)); err != nil {
+		t.Errorf("DecideBatch() error = %v, want the background context authorized", err)
+	}
+}
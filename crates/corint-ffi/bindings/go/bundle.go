@@ -0,0 +1,77 @@
+package corint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TraceBundle collects the traces of a batch of decisions for offline analysis
+type TraceBundle struct {
+	CreatedAt time.Time          `json:"created_at"`
+	Entries   []TraceBundleEntry `json:"entries"`
+}
+
+// TraceBundleEntry is the trace of one decision in a TraceBundle
+type TraceBundleEntry struct {
+	Index     int    `json:"index"`
+	RequestID string `json:"request_id"`
+	Decision  string `json:"decision"`
+	Trace     Trace  `json:"trace"`
+}
+
+// DecideBatchWithTraces executes each request with tracing enabled and returns
+// the responses together with a bundle of all their traces. The caller's
+// requests are not modified.
+func (e *DecisionEngine) DecideBatchWithTraces(requests []*DecisionRequest) ([]*DecisionResponse, *TraceBundle, error) {
+	responses := make([]*DecisionResponse, len(requests))
+	bundle := &TraceBundle{
+		CreatedAt: time.Now().UTC(),
+		Entries:   make([]TraceBundleEntry, 0, len(requests)),
+	}
+
+	for i, request := range requests {
+		if request == nil {
+			return nil, nil, fmt.Errorf("request %d is nil", i)
+		}
+		traced := *request
+		traced.Options.EnableTrace = true
+
+		response, err := e.Decide(&traced)
+		if err != nil {
+			return nil, nil, fmt.Errorf("request %d: %w", i, err)
+		}
+		responses[i] = response
+		bundle.Entries = append(bundle.Entries, TraceBundleEntry{
+			Index:     i,
+			RequestID: response.RequestID,
+			Decision:  response.Decision,
+			Trace:     response.Trace,
+		})
+	}
+
+	return responses, bundle, nil
+}
+
+// WriteFile writes the bundle to path as JSON
+func (b *TraceBundle) WriteFile(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadTraceBundle reads a bundle written by TraceBundle.WriteFile
+func ReadTraceBundle(path string) (*TraceBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle TraceBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
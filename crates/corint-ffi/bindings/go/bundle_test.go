@@ -0,0 +1,65 @@
+package corint
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDecideBatchWithTraces(t *testing.T) {
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		var request struct {
+			Options DecisionOptions `json:"options"`
+		}
+		if err := json.Unmarshal(requestJSON, &request); err != nil || !request.Options.EnableTrace {
+			t.Errorf("native request %s does not enable tracing", requestJSON)
+		}
+		return []byte(fixtureResponseJSON), nil
+	})
+
+	requests := indexedRequests(3)
+	responses, bundle, err := engine.DecideBatchWithTraces(requests)
+	if err != nil {
+		t.Fatalf("DecideBatchWithTraces: %v", err)
+	}
+	for i, request := range requests {
+		if request.Options.EnableTrace {
+			t.Errorf("request %d was modified", i)
+		}
+	}
+
+	if len(responses) != 3 || len(bundle.Entries) != 3 {
+		t.Fatalf("got %d responses and %d entries, want 3 of each", len(responses), len(bundle.Entries))
+	}
+	for i, entry := range bundle.Entries {
+		if responses[i].Trace == nil {
+			t.Errorf("response %d has no trace", i)
+		}
+		if entry.Index != i || entry.RequestID != "req_fixture" || entry.Decision != "decline" || entry.Trace == nil {
+			t.Errorf("entry %d = %+v", i, entry)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := bundle.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	read, err := ReadTraceBundle(path)
+	if err != nil {
+		t.Fatalf("ReadTraceBundle: %v", err)
+	}
+	if !read.CreatedAt.Equal(bundle.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", read.CreatedAt, bundle.CreatedAt)
+	}
+	if len(read.Entries) != 3 || !reflect.DeepEqual(read.Entries[1].Trace.RuleEvals(), bundle.Entries[1].Trace.RuleEvals()) {
+		t.Errorf("read entries do not match the written bundle: %+v", read.Entries)
+	}
+}
+
+func TestDecideBatchWithTracesNilRequest(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(fixtureResponseJSON))
+	if _, _, err := engine.DecideBatchWithTraces([]*DecisionRequest{eventRequest(), nil}); err == nil {
+		t.Fatal("expected an error for a nil request")
+	}
+}
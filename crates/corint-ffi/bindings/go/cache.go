@@ -0,0 +1,159 @@
+package corint
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrEmptyCacheKey is returned when a cache key function produces an empty key
+var ErrEmptyCacheKey = errors.New("cache key function returned an empty key")
+
+// cacheEntry is a cached decision and its expiry time
+type cacheEntry struct {
+	response  *DecisionResponse
+	expiresAt time.Time
+}
+
+// CachingEngine caches successful decisions of an underlying engine
+type CachingEngine struct {
+	engine Engine
+	keyFn  func(*DecisionRequest) string
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	nextSweep time.Time
+	// generation counts Invalidate calls, so decisions started before one are not cached
+	generation uint64
+}
+
+// NewCachingEngineWithKey caches decisions of e for ttl under the key returned
+// by keyFn, so requests that differ only in fields keyFn ignores share an entry.
+// keyFn must be non-nil and ttl positive.
+// Expired entries are swept at most once per ttl as new decisions are cached,
+// so the cache holds roughly the keys seen in the last two ttl periods.
+func NewCachingEngineWithKey(e Engine, keyFn func(*DecisionRequest) string, ttl time.Duration) (*CachingEngine, error) {
+	if keyFn == nil {
+		return nil, errors.New("cache key function is required")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("cache ttl must be positive")
+	}
+	return &CachingEngine{
+		engine:  e,
+		keyFn:   keyFn,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}, nil
+}
+
+// Decide returns the cached decision for the request key, or executes and caches it
+func (c *CachingEngine) Decide(request *DecisionRequest) (*DecisionResponse, error) {
+	key := c.keyFn(request)
+	if key == "" {
+		return nil, ErrEmptyCacheKey
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	generation := c.generation
+	entry, ok := c.entries[key]
+	if ok && now.After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return copyResponse(entry.response), nil
+	}
+
+	response, err := c.engine.Decide(request)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return response, nil
+	}
+	if !now.Before(c.nextSweep) {
+		c.sweep(now)
+		c.nextSweep = now.Add(c.ttl)
+	}
+	c.entries[key] = cacheEntry{response: copyResponse(response), expiresAt: now.Add(c.ttl)}
+	return response, nil
+}
+
+// sweep removes the entries expired at now. The caller must hold c.mu.
+func (c *CachingEngine) sweep(now time.Time) {
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Invalidate removes every cached decision. Decisions still in flight when
+// it is called are returned but not cached.
+func (c *CachingEngine) Invalidate() {
+	c.mu.Lock()
+	c.generation++
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+}
+
+// Len returns the number of cached decisions, including expired ones not yet evicted
+func (c *CachingEngine) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// copyResponse returns a deep copy of r that can be modified without affecting r
+func copyResponse(r *DecisionResponse) *DecisionResponse {
+	copied := *r
+	if r.PipelineID != nil {
+		pipelineID := *r.PipelineID
+		copied.PipelineID = &pipelineID
+	}
+	copied.Result = copyResult(r.Result)
+	copied.Actions = copied.Result.Actions
+	copied.Trace = r.Trace.Clone()
+	if r.Metadata != nil {
+		copied.Metadata = make(map[string]string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			copied.Metadata[k] = v
+		}
+	}
+	if r.Decisions != nil {
+		copied.Decisions = make(map[string]DecisionResult, len(r.Decisions))
+		for k, v := range r.Decisions {
+			copied.Decisions[k] = copyResult(v)
+		}
+	}
+	return &copied
+}
+
+// copyResult returns a deep copy of a decision result
+func copyResult(r DecisionResult) DecisionResult {
+	copied := r
+	if r.Signal != nil {
+		signal := *r.Signal
+		copied.Signal = &signal
+	}
+	copied.Actions = append([]string(nil), r.Actions...)
+	copied.TriggeredRules = append([]string(nil), r.TriggeredRules...)
+	if r.Context != nil {
+		copied.Context = cloneJSONValue(r.Context).(map[string]interface{})
+	}
+	if r.Outputs != nil {
+		copied.Outputs = make(map[string]json.RawMessage, len(r.Outputs))
+		for name, output := range r.Outputs {
+			copied.Outputs[name] = append(json.RawMessage(nil), output...)
+		}
+	}
+	return copied
+}
@@ -0,0 +1,187 @@
+package corint
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// userKey keys cached decisions by the event's user_id
+func userKey(request *DecisionRequest) string {
+	user, _ := request.EventData["user_id"].(string)
+	return user
+}
+
+// userRequest returns a request for user carrying an extra amount field
+func userRequest(user string, amount float64) *DecisionRequest {
+	return &DecisionRequest{EventData: map[string]interface{}{"user_id": user, "amount": amount}}
+}
+
+// countingEngine returns an engine answering a traced decline and the number of decisions it made
+func countingEngine() (Engine, *atomic.Int64) {
+	var calls atomic.Int64
+	engine := EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		calls.Add(1)
+		pipelineID := "login_pipeline"
+		return &DecisionResponse{
+			PipelineID: &pipelineID,
+			Result: DecisionResult{
+				Signal:  &DecisionSignal{Type: "decline"},
+				Actions: []string{"BLOCK"},
+				Context: map[string]interface{}{"device": map[string]interface{}{"trusted": false}},
+				Outputs: map[string]json.RawMessage{"report": json.RawMessage(`{"ok":true}`)},
+			},
+			Trace:     Trace{"pipeline": map[string]interface{}{"pipeline_id": "login_pipeline"}},
+			Decisions: map[string]DecisionResult{"compliance": {Signal: &DecisionSignal{Type: "review"}}},
+			Decision:  "decline",
+			Actions:   []string{"BLOCK"},
+		}, nil
+	})
+	return engine, &calls
+}
+
+func TestCachingEngineSharesEntryAcrossIgnoredFields(t *testing.T) {
+	engine, calls := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+
+	for _, amount := range []float64{10, 20, 30} {
+		response, err := cache.Decide(userRequest("u1", amount))
+		if err != nil || response.Decision != "decline" {
+			t.Fatalf("Decide() = %v, %v", response, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("underlying engine called %d times, want 1", got)
+	}
+	if _, err := cache.Decide(userRequest("u2", 10)); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("underlying engine called %d times for a second key, want 2", got)
+	}
+}
+
+func TestCachingEngineReturnsDeepCopies(t *testing.T) {
+	engine, _ := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+	if _, err := cache.Decide(userRequest("u1", 1)); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	first, err := cache.Decide(userRequest("u1", 1))
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	*first.PipelineID = "changed"
+	first.Result.Signal.Type = "approve"
+	first.Result.Actions[0] = "ALLOW"
+	first.Result.Context["device"].(map[string]interface{})["trusted"] = true
+	first.Result.Outputs["report"][1] = 'X'
+	first.Trace["pipeline"].(map[string]interface{})["pipeline_id"] = "changed"
+	first.Decisions["compliance"].Signal.Type = "approve"
+
+	second, err := cache.Decide(userRequest("u1", 1))
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if *second.PipelineID != "login_pipeline" || second.Result.Signal.Type != "decline" || second.Result.Actions[0] != "BLOCK" {
+		t.Errorf("cached result was modified: %+v", second)
+	}
+	if second.Result.Context["device"].(map[string]interface{})["trusted"] != false {
+		t.Error("cached context was modified")
+	}
+	if string(second.Result.Outputs["report"]) != `{"ok":true}` {
+		t.Errorf("cached output = %s", second.Result.Outputs["report"])
+	}
+	if second.Trace["pipeline"].(map[string]interface{})["pipeline_id"] != "login_pipeline" {
+		t.Error("cached trace was modified")
+	}
+	if second.Decisions["compliance"].Signal.Type != "review" {
+		t.Error("cached sub-decision was modified")
+	}
+}
+
+func TestCachingEngineSweepsExpiredEntries(t *testing.T) {
+	engine, _ := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+
+	for _, user := range []string{"u1", "u2", "u3"} {
+		if _, err := cache.Decide(userRequest(user, 1)); err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+	}
+	time.Sleep(15 * time.Millisecond)
+	if _, err := cache.Decide(userRequest("u4", 1)); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if got := cache.Len(); got != 1 {
+		t.Errorf("Len() = %d after expiry, want only the new entry", got)
+	}
+}
+
+func TestNewCachingEngineWithKeyRequiresKeyFn(t *testing.T) {
+	engine, _ := countingEngine()
+	if cache, err := NewCachingEngineWithKey(engine, nil, time.Minute); err == nil {
+		t.Fatalf("NewCachingEngineWithKey(nil) = %v, want an error", cache)
+	}
+}
+
+func TestNewCachingEngineWithKeyRequiresPositiveTTL(t *testing.T) {
+	engine, _ := countingEngine()
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if cache, err := NewCachingEngineWithKey(engine, userKey, ttl); err == nil {
+			t.Errorf("NewCachingEngineWithKey(ttl %v) = %v, want an error", ttl, cache)
+		}
+	}
+}
+
+func TestCachingEngineEmptyKey(t *testing.T) {
+	engine, _ := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+	if _, err := cache.Decide(eventRequest()); !errors.Is(err, ErrEmptyCacheKey) {
+		t.Errorf("Decide() error = %v, want ErrEmptyCacheKey", err)
+	}
+}
+
+func TestCachingEngineInvalidateDuringDecide(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	engine := EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		close(started)
+		<-release
+		return decided("approve"), nil
+	})
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := cache.Decide(userRequest("u1", 1))
+		done <- err
+	}()
+	<-started
+	cache.Invalidate()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if got := cache.Len(); got != 0 {
+		t.Errorf("Len() = %d, want the decision started before Invalidate left uncached", got)
+	}
+}
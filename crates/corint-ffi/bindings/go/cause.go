@@ -0,0 +1,169 @@
+package corint
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// MinimalCause returns a single leaf condition whose flip would change the
+// decision, as a counterfactual such as "if amount were <= 500 this would
+// approve". It takes the first ruleset whose matched conclusion has the
+// response's signal and, for each of its rules in order, recomputes the total
+// score with that rule flipped: a triggered rule loses its score, an
+// untriggered rule with a recorded score adds it. The first flip whose score
+// concludes with a different signal gives the cause, a condition of that rule
+// whose own flip would flip the rule. Only "total_score <op> N" and "default"
+// conclusions can be re-evaluated; false is returned for other conclusions,
+// when no single flip changes the signal, and when tracing was disabled. A
+// rule's top-level conditions are treated as all required.
+func (r *DecisionResponse) MinimalCause() (ConditionEval, bool) {
+	pipeline := r.Trace.pipeline()
+	if pipeline == nil {
+		return ConditionEval{}, false
+	}
+	ruleset, matched, ok := decidingRuleset(pipeline, r.Decision)
+	if !ok {
+		return ConditionEval{}, false
+	}
+
+	score := 0
+	if matched.TotalScore != nil {
+		score = *matched.TotalScore
+	} else {
+		for _, rule := range ruleset.Rules {
+			if rule.Triggered && rule.Score != nil {
+				score += *rule.Score
+			}
+		}
+	}
+
+	for _, rule := range ruleset.Rules {
+		if rule.Score == nil {
+			continue
+		}
+		flipped := score + *rule.Score
+		if rule.Triggered {
+			flipped = score - *rule.Score
+		}
+
+		signal, ok := concludeScore(ruleset.Conclusion, flipped)
+		if !ok {
+			return ConditionEval{}, false
+		}
+		if signal == matched.Signal {
+			continue
+		}
+		if cause, ok := firstCritical(RuleEval{Triggered: rule.Triggered, Conditions: rule.Conditions}); ok {
+			return cause, true
+		}
+	}
+	return ConditionEval{}, false
+}
+
+// decidingRuleset returns the first ruleset whose matched conclusion has
+// signal, or the first that matched at all when signal is empty
+func decidingRuleset(pipeline *tracePipeline, signal string) (traceRuleset, traceConclusion, bool) {
+	for _, ruleset := range pipeline.Rulesets {
+		for _, conclusion := range ruleset.Conclusion {
+			if !conclusion.Matched {
+				continue
+			}
+			if signal == "" || conclusion.Signal == signal {
+				return ruleset, conclusion, true
+			}
+			break
+		}
+	}
+	return traceRuleset{}, traceConclusion{}, false
+}
+
+// scoreCondition matches re-evaluable conclusion conditions such as "total_score >= 100"
+var scoreCondition = regexp.MustCompile(`^\s*total_score\s*(>=|<=|==|!=|>|<)\s*(-?\d+(?:\.\d+)?)\s*$`)
+
+// concludeScore returns the signal of the first conclusion score satisfies;
+// ok is false when a conclusion before it cannot be re-evaluated
+func concludeScore(conclusions []traceConclusion, score int) (signal string, ok bool) {
+	for _, conclusion := range conclusions {
+		if conclusion.Condition == "default" {
+			return conclusion.Signal, true
+		}
+
+		match := scoreCondition.FindStringSubmatch(conclusion.Condition)
+		if match == nil {
+			return "", false
+		}
+		threshold, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return "", false
+		}
+
+		value := float64(score)
+		var holds bool
+		switch match[1] {
+		case ">=":
+			holds = value >= threshold
+		case "<=":
+			holds = value <= threshold
+		case ">":
+			holds = value > threshold
+		case "<":
+			holds = value < threshold
+		case "==":
+			holds = value == threshold
+		case "!=":
+			holds = value != threshold
+		}
+		if holds {
+			return conclusion.Signal, true
+		}
+	}
+	return "", false
+}
+
+// firstCritical returns the first leaf of a rule whose flip would flip the
+// rule, preferring comparisons with recorded operands
+func firstCritical(eval RuleEval) (ConditionEval, bool) {
+	root := ConditionEval{Result: eval.Triggered, GroupType: "all", Nested: eval.Conditions}
+	candidates := criticalLeaves(root)
+	for _, candidate := range candidates {
+		if candidate.LeftValue != nil || candidate.RightValue != nil {
+			return candidate, true
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0], true
+	}
+	return ConditionEval{}, false
+}
+
+// criticalLeaves returns the leaves of c whose individual flip flips c's result
+func criticalLeaves(c ConditionEval) []ConditionEval {
+	if len(c.Nested) == 0 {
+		if c.GroupType != "" {
+			return nil
+		}
+		return []ConditionEval{c}
+	}
+
+	// Members whose result equals the group's decide it on their own when
+	// they are the only such member; otherwise every member can flip it.
+	unanimous := (c.GroupType == "any") != c.Result
+	if unanimous {
+		var critical []ConditionEval
+		for _, member := range c.Nested {
+			critical = append(critical, criticalLeaves(member)...)
+		}
+		return critical
+	}
+
+	var deciding []ConditionEval
+	for _, member := range c.Nested {
+		if member.Result == c.Result {
+			deciding = append(deciding, member)
+		}
+	}
+	if len(deciding) != 1 {
+		return nil
+	}
+	return criticalLeaves(deciding[0])
+}
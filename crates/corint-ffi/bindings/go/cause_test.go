@@ -0,0 +1,83 @@
+package corint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// rulesetResponse returns a response deciding signal whose trace has one
+// ruleset with the given rules and conclusion JSON arrays
+func rulesetResponse(t *testing.T, signal, rules, conclusion string) *DecisionResponse {
+	t.Helper()
+	var trace Trace
+	data := `{"pipeline":{"pipeline_id":"login_pipeline","rulesets":[{"ruleset_id":"login_risk","rules":` + rules + `,"conclusion":` + conclusion + `}]}}`
+	if err := json.Unmarshal([]byte(data), &trace); err != nil {
+		t.Fatalf("decode trace: %v", err)
+	}
+	return &DecisionResponse{Decision: signal, Trace: trace}
+}
+
+func TestMinimalCauseFixture(t *testing.T) {
+	// Untriggering new_device leaves 60, which concludes review instead of decline
+	cause, ok := fixtureResponse(t).MinimalCause()
+	if !ok {
+		t.Fatal("MinimalCause found no cause")
+	}
+	if cause.Expression != "event.device.is_new == true" {
+		t.Errorf("cause = %q, want event.device.is_new == true", cause.Expression)
+	}
+}
+
+func TestMinimalCauseTriggersUntriggeredRule(t *testing.T) {
+	response := rulesetResponse(t, "approve",
+		`[
+			{"rule_id":"new_device","triggered":true,"score":30,"conditions":[{"expression":"event.device.is_new == true","result":true}]},
+			{"rule_id":"velocity","triggered":false,"score":30,"conditions":[{"expression":"features.txn_count_24h >= 10","left_value":3,"operator":">=","right_value":10,"result":false}]}
+		]`,
+		`[
+			{"condition":"total_score >= 50","matched":false,"signal":"review"},
+			{"condition":"default","matched":true,"signal":"approve","total_score":30}
+		]`)
+
+	// Untriggering new_device still approves at 0; triggering velocity reviews at 60
+	cause, ok := response.MinimalCause()
+	if !ok || cause.Expression != "features.txn_count_24h >= 10" {
+		t.Errorf("MinimalCause() = %q, %v, want the velocity condition", cause.Expression, ok)
+	}
+}
+
+func TestMinimalCauseNoSingleFlip(t *testing.T) {
+	// Either rule alone still reaches the decline threshold
+	response := rulesetResponse(t, "decline",
+		`[
+			{"rule_id":"high_amount","triggered":true,"score":100,"conditions":[{"expression":"event.amount > 500","result":true}]},
+			{"rule_id":"blocklisted","triggered":true,"score":100,"conditions":[{"expression":"event.ip in list.blocked_ips","result":true}]}
+		]`,
+		`[
+			{"condition":"total_score >= 100","matched":true,"signal":"decline","total_score":200},
+			{"condition":"default","matched":false,"signal":"approve"}
+		]`)
+
+	if cause, ok := response.MinimalCause(); ok {
+		t.Errorf("MinimalCause() = %q, want none when no single flip changes the signal", cause.Expression)
+	}
+}
+
+func TestMinimalCauseUnsupportedConclusion(t *testing.T) {
+	response := rulesetResponse(t, "decline",
+		`[{"rule_id":"high_amount","triggered":true,"score":100,"conditions":[{"expression":"event.amount > 500","result":true}]}]`,
+		`[
+			{"condition":"event.country == \"US\"","matched":true,"signal":"decline"},
+			{"condition":"default","matched":false,"signal":"approve"}
+		]`)
+
+	if cause, ok := response.MinimalCause(); ok {
+		t.Errorf("MinimalCause() = %q, want none for a conclusion it cannot re-evaluate", cause.Expression)
+	}
+}
+
+func TestMinimalCauseWithoutTrace(t *testing.T) {
+	if cause, ok := decided("decline", "BLOCK").MinimalCause(); ok {
+		t.Errorf("MinimalCause() = %q, want none without a trace", cause.Expression)
+	}
+}
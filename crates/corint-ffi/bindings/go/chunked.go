@@ -0,0 +1,127 @@
+package corint
+
+/*
+#include <stddef.h>
+#include <stdlib.h>
+
+void* corint_engine_decide_chunked(void* engine, const char* request_json);
+ptrdiff_t corint_read_chunk(void* reader, unsigned char* buf, size_t buf_len);
+void corint_response_reader_free(void* reader);
+*/
+import "C"
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// chunkedResponsesCapability is the native capability required by WithChunkedResponses
+const chunkedResponsesCapability = "chunked_responses"
+
+// errChunkRead is returned when the native chunk reader fails mid-response
+var errChunkRead = errors.New("failed to read native response chunk")
+
+// WithChunkedResponses makes Decide read native responses through a reader in
+// pieces of at most chunkSize bytes, decoding them as they arrive instead of
+// first copying the whole response out of a single C string. encoding/json
+// still buffers a complete value before decoding it, so this bounds each copy
+// across the FFI boundary rather than the response held in memory. Native
+// libraries without the "chunked_responses" capability, and
+// DecideInRepository, use the single-string path.
+func WithChunkedResponses(chunkSize int) EngineOption {
+	return func(c *engineConfig) {
+		if chunkSize > 0 {
+			c.responseChunkSize = chunkSize
+		}
+	}
+}
+
+var (
+	chunkedOnce      sync.Once
+	chunkedSupported bool
+)
+
+// chunkedResponsesSupported reports, checking once per process, whether the
+// native library offers chunked responses
+func chunkedResponsesSupported() bool {
+	chunkedOnce.Do(func() {
+		info, err := VersionInfo()
+		if err != nil {
+			return
+		}
+		for _, capability := range info.Capabilities {
+			if capability == chunkedResponsesCapability {
+				chunkedSupported = true
+			}
+		}
+	})
+	return chunkedSupported
+}
+
+// decideChunked executes a decision through the native chunk reader
+func (e *DecisionEngine) decideChunked(requestJSON []byte) (io.ReadCloser, error) {
+	read, free, err := nativeDecideChunked(e.handle, requestJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkReader{read: read, free: free, size: e.config.responseChunkSize}, nil
+}
+
+// nativeDecideChunked starts a chunked decision on a native engine handle,
+// returning functions that copy the next chunk into a buffer and free the
+// reader; tests replace it to fake large native responses
+var nativeDecideChunked = func(handle unsafe.Pointer, requestJSON []byte) (read func(buf []byte) int, free func(), err error) {
+	cRequest := C.CString(string(requestJSON))
+	defer C.free(unsafe.Pointer(cRequest))
+
+	reader := C.corint_engine_decide_chunked(handle, cRequest)
+	if reader == nil {
+		return nil, nil, errDecisionFailed
+	}
+	read = func(buf []byte) int {
+		return int(C.corint_read_chunk(reader, (*C.uchar)(unsafe.Pointer(&buf[0])), C.size_t(len(buf))))
+	}
+	free = func() {
+		C.corint_response_reader_free(reader)
+	}
+	return read, free, nil
+}
+
+// chunkReader is an io.ReadCloser over a native chunked response
+type chunkReader struct {
+	read func(buf []byte) int
+	free func()
+	size int
+}
+
+// Read copies at most size bytes of the response into p
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.free == nil {
+		return 0, errChunkRead
+	}
+	if len(p) > r.size {
+		p = p[:r.size]
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	n := r.read(p)
+	switch {
+	case n < 0:
+		return 0, errChunkRead
+	case n == 0:
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+// Close frees the native reader; it is safe to call more than once
+func (r *chunkReader) Close() error {
+	if r.free != nil {
+		r.free()
+		r.free = nil
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package corint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// fakeChunks records how a fake native chunked response was read
+type fakeChunks struct {
+	reads   int
+	maxRead int
+	freed   int
+}
+
+// fakeChunkedNative serves response through a fake native chunk reader,
+// failing reads once failAfter bytes were read when failAfter is positive.
+// capabilities is the native capability list reported to the binding.
+func fakeChunkedNative(t *testing.T, response string, failAfter int, capabilities string) *fakeChunks {
+	t.Helper()
+	chunks := &fakeChunks{}
+	original := nativeDecideChunked
+	nativeDecideChunked = func(_ unsafe.Pointer, _ []byte) (func([]byte) int, func(), error) {
+		data := bytes.NewReader([]byte(response))
+		read := func(buf []byte) int {
+			chunks.reads++
+			if len(buf) > chunks.maxRead {
+				chunks.maxRead = len(buf)
+			}
+			if failAfter > 0 && int(data.Size())-data.Len() >= failAfter {
+				return -1
+			}
+			n, _ := data.Read(buf)
+			return n
+		}
+		return read, func() { chunks.freed++ }, nil
+	}
+	fakeVersionInfo(t, fmt.Sprintf(`{"version":"0.1.0","abi_version":1,"capabilities":%s}`, capabilities))
+	chunkedOnce, chunkedSupported = sync.Once{}, false
+	t.Cleanup(func() {
+		nativeDecideChunked = original
+		chunkedOnce, chunkedSupported = sync.Once{}, false
+	})
+	return chunks
+}
+
+// largeTracedResponse returns a declined native response whose trace records rules rule evaluations
+func largeTracedResponse(rules int) string {
+	evals := make([]string, rules)
+	for i := range evals {
+		evals[i] = fmt.Sprintf(`{"rule_id":"rule_%d","triggered":false,"score":1,"conditions":[{"expression":"event.amount > %d","left_value":1,"operator":">","right_value":%d,"result":false}]}`, i, i, i)
+	}
+	return `{"request_id":"req_large","result":{"signal":{"type":"decline"},"actions":["BLOCK"],"score":100,"triggered_rules":[],"explanation":"","context":{}},"processing_time_ms":1,` +
+		`"trace":{"pipeline":{"pipeline_id":"login_pipeline","rulesets":[{"ruleset_id":"login_risk","rules":[` + strings.Join(evals, ",") + `]}]}}}`
+}
+
+func TestDecideChunkedLargeResponse(t *testing.T) {
+	const chunkSize = 4096
+	response := largeTracedResponse(5000)
+	chunks := fakeChunkedNative(t, response, 0, `["chunked_responses"]`)
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		t.Error("chunked engine used the single-string path")
+		return []byte(approveResponse), nil
+	}, WithChunkedResponses(chunkSize))
+
+	request := eventRequest()
+	request.Options.EnableTrace = true
+	decision, err := engine.Decide(request)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if decision.Decision != "decline" || len(decision.Trace.RuleEvals()) != 5000 {
+		t.Errorf("decision = %q with %d rule evals, want decline with 5000", decision.Decision, len(decision.Trace.RuleEvals()))
+	}
+	if chunks.maxRead > chunkSize {
+		t.Errorf("largest native read = %d bytes, want at most %d", chunks.maxRead, chunkSize)
+	}
+	if minReads := len(response) / chunkSize; chunks.reads < minReads {
+		t.Errorf("native reads = %d, want at least %d for a %d-byte response", chunks.reads, minReads, len(response))
+	}
+	if chunks.freed != 1 {
+		t.Errorf("native reader freed %d times, want once", chunks.freed)
+	}
+}
+
+func TestDecideChunkedErrorEnvelope(t *testing.T) {
+	chunks := fakeChunkedNative(t, `{"error":"rule compile failed","success":false,"diagnostics":{"error_kind":"compile"}}`, 0, `["chunked_responses"]`)
+	engine := newFakeEngine(t, respondWith(approveResponse), WithChunkedResponses(8))
+
+	_, err := engine.Decide(eventRequest())
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) || decisionErr.Kind != "compile" {
+		t.Fatalf("Decide() error = %v, want a compile DecisionError", err)
+	}
+	if chunks.freed != 1 {
+		t.Errorf("native reader freed %d times, want once", chunks.freed)
+	}
+}
+
+func TestDecideChunkedReadFailure(t *testing.T) {
+	chunks := fakeChunkedNative(t, largeTracedResponse(100), 1024, `["chunked_responses"]`)
+	engine := newFakeEngine(t, respondWith(approveResponse), WithChunkedResponses(256))
+
+	if _, err := engine.Decide(eventRequest()); !errors.Is(err, errChunkRead) {
+		t.Fatalf("Decide() error = %v, want errChunkRead", err)
+	}
+	if chunks.freed != 1 {
+		t.Errorf("native reader freed %d times after a failed read, want once", chunks.freed)
+	}
+}
+
+func TestDecideChunkedFallsBackWithoutCapability(t *testing.T) {
+	chunks := fakeChunkedNative(t, largeTracedResponse(1), 0, `[]`)
+	engine := newFakeEngine(t, respondWith(approveResponse), WithChunkedResponses(64))
+
+	decision, err := engine.Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if decision.Decision != "approve" || chunks.reads != 0 {
+		t.Errorf("decision = %q after %d chunk reads, want the single-string approve", decision.Decision, chunks.reads)
+	}
+}
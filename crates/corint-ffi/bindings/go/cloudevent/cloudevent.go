@@ -0,0 +1,92 @@
+// Package cloudevent converts CORINT decisions into CloudEvents 1.0 envelopes.
+//
+// Events use the JSON event format, so they can be published to any event
+// bus without pulling a CloudEvents SDK into the core binding.
+package cloudevent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	corint "github.com/corint/corint-go"
+)
+
+// SpecVersion is the CloudEvents specification version produced
+const SpecVersion = "1.0"
+
+// decisionTypePrefix prefixes the event type; the decision signal is appended
+const decisionTypePrefix = "com.corint.decision."
+
+// Event is a CloudEvents 1.0 event in structured JSON mode
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// DecisionData is the event payload
+type DecisionData struct {
+	RequestID        string            `json:"request_id"`
+	PipelineID       string            `json:"pipeline_id,omitempty"`
+	Decision         string            `json:"decision"`
+	Actions          []string          `json:"actions"`
+	Score            int               `json:"score"`
+	TriggeredRules   []string          `json:"triggered_rules"`
+	ProcessingTimeMs uint64            `json:"processing_time_ms"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// FromDecision maps a decision response into an event of type
+// "com.corint.decision.<signal>" with the decision and actions as data
+func FromDecision(r *corint.DecisionResponse, source, subject string) (Event, error) {
+	if r == nil {
+		return Event{}, errors.New("decision response is nil")
+	}
+	if source == "" {
+		return Event{}, errors.New("event source is required")
+	}
+	if r.RequestID == "" {
+		return Event{}, errors.New("decision response has no request ID")
+	}
+
+	data := DecisionData{
+		RequestID:        r.RequestID,
+		Decision:         r.Decision,
+		Actions:          append([]string{}, r.Actions...),
+		Score:            r.Result.Score,
+		TriggeredRules:   append([]string{}, r.Result.TriggeredRules...),
+		ProcessingTimeMs: r.ProcessingTimeMs,
+		Metadata:         r.Metadata,
+	}
+	if r.PipelineID != nil {
+		data.PipelineID = *r.PipelineID
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("encode event data: %w", err)
+	}
+
+	decision := r.Decision
+	if decision == "" {
+		decision = "none"
+	}
+
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              r.RequestID,
+		Source:          source,
+		Type:            decisionTypePrefix + decision,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            payload,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package cloudevent
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corint "github.com/corint/corint-go"
+)
+
+func TestFromDecision(t *testing.T) {
+	pipelineID := "login_pipeline"
+	response := &corint.DecisionResponse{
+		RequestID:        "req_42",
+		PipelineID:       &pipelineID,
+		Result:           corint.DecisionResult{Score: 80, TriggeredRules: []string{"new_device"}},
+		ProcessingTimeMs: 3,
+		Metadata:         map[string]string{"tenant_id": "acme"},
+		Decision:         "review",
+		Actions:          []string{"OTP"},
+	}
+
+	event, err := FromDecision(response, "/corint/login", "user-7")
+	if err != nil {
+		t.Fatalf("FromDecision: %v", err)
+	}
+	if event.SpecVersion != "1.0" || event.ID != "req_42" || event.Type != "com.corint.decision.review" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Source != "/corint/login" || event.Subject != "user-7" || event.DataContentType != "application/json" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Time.IsZero() {
+		t.Error("event time is not set")
+	}
+
+	var data DecisionData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	want := DecisionData{
+		RequestID:        "req_42",
+		PipelineID:       "login_pipeline",
+		Decision:         "review",
+		Actions:          []string{"OTP"},
+		Score:            80,
+		TriggeredRules:   []string{"new_device"},
+		ProcessingTimeMs: 3,
+		Metadata:         map[string]string{"tenant_id": "acme"},
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %+v, want %+v", data, want)
+	}
+}
+
+func TestFromDecisionWithoutSignal(t *testing.T) {
+	event, err := FromDecision(&corint.DecisionResponse{RequestID: "req_1"}, "/corint", "")
+	if err != nil {
+		t.Fatalf("FromDecision: %v", err)
+	}
+	if event.Type != "com.corint.decision.none" {
+		t.Errorf("Type = %q, want com.corint.decision.none", event.Type)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if actions, ok := data["actions"].([]interface{}); !ok || len(actions) != 0 {
+		t.Errorf("actions = %v, want an empty array", data["actions"])
+	}
+}
+
+func TestFromDecisionInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *corint.DecisionResponse
+		source   string
+	}{
+		{"nil response", nil, "/corint"},
+		{"no source", &corint.DecisionResponse{RequestID: "req_1"}, ""},
+		{"no request ID", &corint.DecisionResponse{}, "/corint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromDecision(tt.response, tt.source, ""); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
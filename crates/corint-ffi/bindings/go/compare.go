@@ -0,0 +1,156 @@
+package corint
+
+import "math"
+
+// ComparisonReport summarizes how two index-aligned sets of decisions differ
+type ComparisonReport struct {
+	// Compared is the number of index pairs where both responses were present
+	Compared int
+	// Agreements is the number of compared pairs with the same decision
+	Agreements int
+	// AgreementRate is Agreements divided by Compared, or 0 when nothing was compared
+	AgreementRate float64
+	// Transitions counts decisions keyed by the A decision and then the B decision,
+	// so Transitions["approve"]["decline"] is the number of A approvals B declined
+	Transitions map[string]map[string]int
+	// ActionDiffs lists the compared pairs whose actions differ
+	ActionDiffs []ActionDiff
+	// ScoreDiffs lists the compared pairs whose scores differ by more than the tolerance
+	ScoreDiffs []ScoreDiff
+	// Unpaired is the number of indices missing a response on either side
+	Unpaired int
+}
+
+// ActionDiff describes the action differences for a single index pair
+type ActionDiff struct {
+	Index   int
+	OnlyInA []string
+	OnlyInB []string
+}
+
+// ScoreDiff describes a score difference for a single index pair
+type ScoreDiff struct {
+	Index  int
+	ScoreA int
+	ScoreB int
+}
+
+// CompareOption configures how responses are compared
+type CompareOption func(*compareConfig)
+
+// compareConfig holds the settings applied by CompareOption values
+type compareConfig struct {
+	scoreTolerance float64
+}
+
+// WithFloatTolerance treats scores as equal when they differ by at most epsilon
+func WithFloatTolerance(epsilon float64) CompareOption {
+	return func(c *compareConfig) {
+		c.scoreTolerance = math.Abs(epsilon)
+	}
+}
+
+// scoresEqual reports whether two scores are within the configured tolerance
+func (c compareConfig) scoresEqual(a, b int) bool {
+	return math.Abs(float64(a)-float64(b)) <= c.scoreTolerance
+}
+
+// newCompareConfig applies opts on top of exact comparison
+func newCompareConfig(opts []CompareOption) compareConfig {
+	var config compareConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// EqualDecisions reports whether two responses have the same decision, the
+// same actions and scores within the configured tolerance
+func EqualDecisions(a, b *DecisionResponse, opts ...CompareOption) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	config := newCompareConfig(opts)
+	return a.Decision == b.Decision &&
+		len(subtractActions(a.Actions, b.Actions)) == 0 &&
+		len(subtractActions(b.Actions, a.Actions)) == 0 &&
+		config.scoresEqual(a.Result.Score, b.Result.Score)
+}
+
+// CompareDecisions aligns two result sets by index and reports agreement,
+// the decision transition matrix, and action and score differences
+func CompareDecisions(a, b []*DecisionResponse, opts ...CompareOption) ComparisonReport {
+	config := newCompareConfig(opts)
+	report := ComparisonReport{Transitions: make(map[string]map[string]int)}
+
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		var left, right *DecisionResponse
+		if i < len(a) {
+			left = a[i]
+		}
+		if i < len(b) {
+			right = b[i]
+		}
+		if left == nil || right == nil {
+			report.Unpaired++
+			continue
+		}
+
+		report.Compared++
+		if left.Decision == right.Decision {
+			report.Agreements++
+		}
+
+		row, ok := report.Transitions[left.Decision]
+		if !ok {
+			row = make(map[string]int)
+			report.Transitions[left.Decision] = row
+		}
+		row[right.Decision]++
+
+		onlyA := subtractActions(left.Actions, right.Actions)
+		onlyB := subtractActions(right.Actions, left.Actions)
+		if len(onlyA) > 0 || len(onlyB) > 0 {
+			report.ActionDiffs = append(report.ActionDiffs, ActionDiff{
+				Index:   i,
+				OnlyInA: onlyA,
+				OnlyInB: onlyB,
+			})
+		}
+
+		if !config.scoresEqual(left.Result.Score, right.Result.Score) {
+			report.ScoreDiffs = append(report.ScoreDiffs, ScoreDiff{
+				Index:  i,
+				ScoreA: left.Result.Score,
+				ScoreB: right.Result.Score,
+			})
+		}
+	}
+
+	if report.Compared > 0 {
+		report.AgreementRate = float64(report.Agreements) / float64(report.Compared)
+	}
+
+	return report
+}
+
+// subtractActions returns the actions in from that are not present in other
+func subtractActions(from, other []string) []string {
+	present := make(map[string]struct{}, len(other))
+	for _, action := range other {
+		present[action] = struct{}{}
+	}
+
+	var missing []string
+	for _, action := range from {
+		if _, ok := present[action]; !ok {
+			missing = append(missing, action)
+		}
+	}
+	return missing
+}
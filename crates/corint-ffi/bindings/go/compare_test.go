@@ -0,0 +1,97 @@
+package corint
+
+import (
+	"reflect"
+	"testing"
+)
+
+// decided returns a response with the given decision and actions
+func decided(decision string, actions ...string) *DecisionResponse {
+	return &DecisionResponse{
+		Result:   DecisionResult{Signal: &DecisionSignal{Type: decision}, Actions: actions},
+		Decision: decision,
+		Actions:  actions,
+	}
+}
+
+func TestCompareDecisionsTransitions(t *testing.T) {
+	a := []*DecisionResponse{
+		decided("approve"),
+		decided("approve"),
+		decided("approve", "OTP"),
+		decided("decline", "BLOCK"),
+		decided("review"),
+		nil,
+	}
+	b := []*DecisionResponse{
+		decided("approve"),
+		decided("decline"),
+		decided("approve", "KYC"),
+		decided("decline", "BLOCK"),
+		decided("approve"),
+		decided("approve"),
+		decided("review"),
+	}
+
+	report := CompareDecisions(a, b)
+
+	wantTransitions := map[string]map[string]int{
+		"approve": {"approve": 2, "decline": 1},
+		"decline": {"decline": 1},
+		"review":  {"approve": 1},
+	}
+	if !reflect.DeepEqual(report.Transitions, wantTransitions) {
+		t.Errorf("Transitions = %v, want %v", report.Transitions, wantTransitions)
+	}
+	if report.Compared != 5 || report.Agreements != 3 || report.Unpaired != 2 {
+		t.Errorf("Compared/Agreements/Unpaired = %d/%d/%d, want 5/3/2", report.Compared, report.Agreements, report.Unpaired)
+	}
+	if report.AgreementRate != 0.6 {
+		t.Errorf("AgreementRate = %v, want 0.6", report.AgreementRate)
+	}
+
+	wantDiffs := []ActionDiff{{Index: 2, OnlyInA: []string{"OTP"}, OnlyInB: []string{"KYC"}}}
+	if !reflect.DeepEqual(report.ActionDiffs, wantDiffs) {
+		t.Errorf("ActionDiffs = %+v, want %+v", report.ActionDiffs, wantDiffs)
+	}
+}
+
+func TestCompareDecisionsEmpty(t *testing.T) {
+	report := CompareDecisions(nil, nil)
+	if report.Compared != 0 || report.AgreementRate != 0 || len(report.Transitions) != 0 {
+		t.Errorf("CompareDecisions(nil, nil) = %+v, want an empty report", report)
+	}
+}
+
+// scored returns an approval with the given score
+func scored(score int) *DecisionResponse {
+	response := decided("approve")
+	response.Result.Score = score
+	return response
+}
+
+func TestCompareScoreTolerance(t *testing.T) {
+	a := []*DecisionResponse{scored(50), scored(50), scored(50)}
+	b := []*DecisionResponse{scored(50), scored(52), scored(53)}
+
+	exact := CompareDecisions(a, b)
+	if len(exact.ScoreDiffs) != 2 {
+		t.Errorf("exact comparison found %d score diffs, want 2", len(exact.ScoreDiffs))
+	}
+
+	tolerant := CompareDecisions(a, b, WithFloatTolerance(2))
+	want := []ScoreDiff{{Index: 2, ScoreA: 50, ScoreB: 53}}
+	if !reflect.DeepEqual(tolerant.ScoreDiffs, want) {
+		t.Errorf("ScoreDiffs = %+v, want %+v", tolerant.ScoreDiffs, want)
+	}
+
+	if !EqualDecisions(scored(50), scored(52), WithFloatTolerance(-2)) {
+		t.Error("scores within a negative epsilon's magnitude compared unequal")
+	}
+	if EqualDecisions(scored(50), scored(53), WithFloatTolerance(2)) {
+		t.Error("scores outside epsilon compared equal")
+	}
+	if EqualDecisions(scored(50), scored(51)) {
+		t.Error("different scores compared equal without a tolerance")
+	}
+}
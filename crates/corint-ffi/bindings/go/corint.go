@@ -6,6 +6,7 @@ package corint
 #cgo linux LDFLAGS: -Wl,-rpath,${SRCDIR}/../../../../target/release
 
 #include <stdlib.h>
+#include <string.h>
 
 // Forward declarations of C functions
 void* corint_engine_new(const char* repository_path);
@@ -13,19 +14,51 @@ void* corint_engine_new_from_database(const char* database_url);
 char* corint_engine_decide(void* engine, const char* request_json);
 void corint_engine_free(void* engine);
 void corint_string_free(char* s);
+size_t corint_live_strings();
 char* corint_version();
+char* corint_version_info();
 void corint_init_logging();
 */
 import "C"
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
+// ErrEmptyResponse is returned when the native engine produces a response without a decision
+var ErrEmptyResponse = errors.New("native engine returned an empty decision response")
+
+// ErrUnknownRepository is returned when deciding against a repository name the engine did not load
+var ErrUnknownRepository = errors.New("unknown repository")
+
+// errDecisionFailed is returned when a native decide call produces no response
+var errDecisionFailed = errors.New("decision execution failed")
+
+// ErrUnauthorized is returned when the engine's authorizer rejects a request
+var ErrUnauthorized = errors.New("decision request unauthorized")
+
+// ErrStaleEvent is returned when a request's EventTime is older than the engine's maximum event age
+var ErrStaleEvent = errors.New("event is older than the maximum event age")
+
 // DecisionOptions represents request options
 type DecisionOptions struct {
 	EnableTrace bool `json:"enable_trace"`
+	// MaxTraceBytes caps the serialized trace size; larger traces are truncated natively
+	MaxTraceBytes int `json:"max_trace_bytes,omitempty"`
+	// FirstActionOnly returns only the first action declared by the matched
+	// conclusion. Rulesets list a conclusion's actions in priority order.
+	FirstActionOnly bool `json:"first_action_only,omitempty"`
+	// EvaluationTime, if set, is used as the current time for sys time variables
+	// so time-dependent rules can be evaluated reproducibly
+	EvaluationTime *time.Time `json:"eval_time,omitempty"`
 }
 
 // DecisionSignal represents the decision signal
@@ -35,46 +68,105 @@ type DecisionSignal struct {
 
 // DecisionResult represents the decision result payload
 type DecisionResult struct {
-	Signal         *DecisionSignal       `json:"signal"`
-	Actions        []string              `json:"actions"`
-	Score          int                   `json:"score"`
-	TriggeredRules []string              `json:"triggered_rules"`
-	Explanation    string                `json:"explanation"`
+	Signal         *DecisionSignal        `json:"signal"`
+	Actions        []string               `json:"actions"`
+	Score          int                    `json:"score"`
+	TriggeredRules []string               `json:"triggered_rules"`
+	Explanation    string                 `json:"explanation"`
 	Context        map[string]interface{} `json:"context"`
+	// Outputs holds named artifacts produced by rules, if any
+	Outputs map[string]json.RawMessage `json:"outputs,omitempty"`
 }
 
 // DecisionRequest represents a decision request
 type DecisionRequest struct {
-	EventData map[string]interface{}   `json:"event_data"`
-	Features  map[string]interface{}   `json:"features,omitempty"`
-	API       map[string]interface{}   `json:"api,omitempty"`
-	Service   map[string]interface{}   `json:"service,omitempty"`
-	LLM       map[string]interface{}   `json:"llm,omitempty"`
-	Vars      map[string]interface{}   `json:"vars,omitempty"`
-	Metadata  map[string]string        `json:"metadata,omitempty"`
-	Options   DecisionOptions          `json:"options"`
+	EventData map[string]interface{} `json:"event_data"`
+	Features  map[string]interface{} `json:"features,omitempty"`
+	API       map[string]interface{} `json:"api,omitempty"`
+	Service   map[string]interface{} `json:"service,omitempty"`
+	LLM       map[string]interface{} `json:"llm,omitempty"`
+	Vars      map[string]interface{} `json:"vars,omitempty"`
+	Metadata  map[string]string      `json:"metadata,omitempty"`
+	Options   DecisionOptions        `json:"options"`
+
+	// EventTime is when the event occurred. It is checked against WithMaxEventAge
+	// and is not sent to the native engine.
+	EventTime *time.Time `json:"-"`
 }
 
 // DecisionResponse represents a decision response
 type DecisionResponse struct {
-	RequestID        string                 `json:"request_id"`
-	PipelineID       *string                `json:"pipeline_id,omitempty"`
-	Result           DecisionResult         `json:"result"`
-	ProcessingTimeMs uint64                 `json:"processing_time_ms"`
-	Metadata         map[string]string      `json:"metadata,omitempty"`
-	Trace            map[string]interface{} `json:"trace,omitempty"`
+	RequestID        string            `json:"request_id"`
+	PipelineID       *string           `json:"pipeline_id,omitempty"`
+	Result           DecisionResult    `json:"result"`
+	ProcessingTimeMs uint64            `json:"processing_time_ms"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	Trace            Trace             `json:"trace,omitempty"`
+	// Decisions holds named sub-decisions for repositories that produce several verdicts
+	Decisions map[string]DecisionResult `json:"decisions,omitempty"`
 
 	Decision string   `json:"-"`
 	Actions  []string `json:"-"`
 }
 
+// Output returns the raw JSON of the named output artifact
+func (r *DecisionResponse) Output(name string) (json.RawMessage, bool) {
+	output, ok := r.Result.Outputs[name]
+	return output, ok
+}
+
+// SubDecision returns the signal of the named sub-decision, such as a separate
+// compliance verdict, the way Decision holds the signal of Result. The full
+// result is available in Decisions. The signal is empty for a sub-decision
+// without one; ok reports whether the sub-decision exists.
+func (r *DecisionResponse) SubDecision(name string) (decision string, ok bool) {
+	result, ok := r.Decisions[name]
+	if ok && result.Signal != nil {
+		decision = result.Signal.Type
+	}
+	return decision, ok
+}
+
+// Engine is the decision interface implemented by DecisionEngine and test doubles
+type Engine interface {
+	Decide(request *DecisionRequest) (*DecisionResponse, error)
+}
+
+// ContextEngine is an Engine that also takes a per-decision context, such as
+// DecisionEngine passing it to its authorizer
+type ContextEngine interface {
+	Engine
+	DecideContext(ctx context.Context, request *DecisionRequest) (*DecisionResponse, error)
+}
+
+// decideContext decides request with ctx when e is a ContextEngine, and
+// with Decide otherwise
+func decideContext(ctx context.Context, e Engine, request *DecisionRequest) (*DecisionResponse, error) {
+	if ce, ok := e.(ContextEngine); ok {
+		return ce.DecideContext(ctx, request)
+	}
+	return e.Decide(request)
+}
+
 // DecisionEngine represents a CORINT decision engine
 type DecisionEngine struct {
-	handle unsafe.Pointer
+	handle   unsafe.Pointer
+	config   engineConfig
+	requests atomic.Uint64
+
+	// togglesMu guards reloads, the number of Reload calls in flight, and
+	// reloadToggles, the rule toggles set while they run
+	togglesMu     sync.Mutex
+	reloads       int
+	reloadToggles map[string]bool
 }
 
 // NewEngine creates a new decision engine from a file system repository
-func NewEngine(repositoryPath string) (*DecisionEngine, error) {
+func NewEngine(repositoryPath string, opts ...EngineOption) (*DecisionEngine, error) {
+	if err := CheckABI(); err != nil {
+		return nil, err
+	}
+
 	cPath := C.CString(repositoryPath)
 	defer C.free(unsafe.Pointer(cPath))
 
@@ -83,11 +175,15 @@ func NewEngine(repositoryPath string) (*DecisionEngine, error) {
 		return nil, errors.New("failed to create decision engine")
 	}
 
-	return &DecisionEngine{handle: handle}, nil
+	return &DecisionEngine{handle: handle, config: newEngineConfig(opts)}, nil
 }
 
 // NewEngineFromDatabase creates a new decision engine from a database
-func NewEngineFromDatabase(databaseURL string) (*DecisionEngine, error) {
+func NewEngineFromDatabase(databaseURL string, opts ...EngineOption) (*DecisionEngine, error) {
+	if err := CheckABI(); err != nil {
+		return nil, err
+	}
+
 	cURL := C.CString(databaseURL)
 	defer C.free(unsafe.Pointer(cURL))
 
@@ -96,71 +192,295 @@ func NewEngineFromDatabase(databaseURL string) (*DecisionEngine, error) {
 		return nil, errors.New("failed to create decision engine from database")
 	}
 
-	return &DecisionEngine{handle: handle}, nil
+	return &DecisionEngine{handle: handle, config: newEngineConfig(opts)}, nil
 }
 
 // Decide executes a decision
 func (e *DecisionEngine) Decide(request *DecisionRequest) (*DecisionResponse, error) {
-	if e.handle == nil {
-		return nil, errors.New("engine has been closed")
-	}
+	return e.DecideContext(context.Background(), request)
+}
 
-	// Convert request to JSON
-	requestJSON, err := json.Marshal(request)
+// DecideContext executes a decision, passing ctx to the engine's authorizer
+func (e *DecisionEngine) DecideContext(ctx context.Context, request *DecisionRequest) (*DecisionResponse, error) {
+	return e.decide(ctx, request, func(requestJSON []byte) (io.ReadCloser, error) {
+		if e.config.responseChunkSize > 0 && chunkedResponsesSupported() {
+			return e.decideChunked(requestJSON)
+		}
+		return wholeResponse(nativeDecide(e.handle, requestJSON))
+	})
+}
+
+// nativeDecide executes requestJSON on a native engine handle and returns the
+// response JSON; tests replace it to fake native responses
+var nativeDecide = func(handle unsafe.Pointer, requestJSON []byte) ([]byte, error) {
+	cRequest := C.CString(string(requestJSON))
+	defer C.free(unsafe.Pointer(cRequest))
+	return nativeResult(C.corint_engine_decide(handle, cRequest))
+}
+
+// wholeResponse wraps a response copied out of a single native string as a reader
+func wholeResponse(resultJSON []byte, err error) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	return io.NopCloser(bytes.NewReader(resultJSON)), nil
+}
 
-	cRequest := C.CString(string(requestJSON))
-	defer C.free(unsafe.Pointer(cRequest))
+// nativeResponse is a decoded native response, which is either a decision or
+// an error envelope
+type nativeResponse struct {
+	DecisionResponse
+	Error        string       `json:"error"`
+	ErrorCode    string       `json:"error_code"`
+	Diagnostics  *Diagnostics `json:"diagnostics"`
+	RetryAfterMs int64        `json:"retry_after_ms"`
+}
 
-	// Call FFI function
-	resultPtr := C.corint_engine_decide(e.handle, cRequest)
-	if resultPtr == nil {
-		return nil, errors.New("decision execution failed")
+// decide runs the shared request/response handling around a native decide call
+func (e *DecisionEngine) decide(ctx context.Context, request *DecisionRequest, call func(requestJSON []byte) (io.ReadCloser, error)) (*DecisionResponse, error) {
+	if e.handle == nil {
+		return nil, ErrEngineClosed
 	}
-	defer C.corint_string_free(resultPtr)
+	e.requests.Add(1)
 
-	// Convert result to string
-	resultJSON := C.GoString(resultPtr)
+	if e.config.authorizer != nil {
+		if err := e.config.authorizer(ctx, request); err != nil {
+			if errors.Is(err, ErrUnauthorized) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+	}
 
-	var errorResp struct {
-		Error   string `json:"error"`
-		Success bool   `json:"success"`
+	if request != nil {
+		if err := request.Options.Validate(); err != nil {
+			return nil, err
+		}
 	}
-	if json.Unmarshal([]byte(resultJSON), &errorResp) == nil && errorResp.Error != "" {
-		return nil, errors.New(errorResp.Error)
+
+	if request == nil || len(request.EventData) == 0 {
+		if e.config.emptyEventDecision == "" {
+			return nil, ErrEmptyEventData
+		}
+		return &DecisionResponse{
+			Result:   DecisionResult{Signal: &DecisionSignal{Type: e.config.emptyEventDecision}},
+			Decision: e.config.emptyEventDecision,
+		}, nil
 	}
 
-	// Parse response
-	var response DecisionResponse
-	if err := json.Unmarshal([]byte(resultJSON), &response); err != nil {
+	if e.config.maxEventAge > 0 && request != nil && request.EventTime != nil {
+		if age := time.Since(*request.EventTime); age > e.config.maxEventAge {
+			return nil, fmt.Errorf("%w: event age %s exceeds %s", ErrStaleEvent, age, e.config.maxEventAge)
+		}
+	}
+
+	if e.config.featureStore != nil {
+		enriched, err := e.config.featureStore.enrich(ctx, request, e.config.featureFetchPolicy)
+		if err != nil {
+			return nil, err
+		}
+		request = enriched
+	}
+
+	// Convert request to JSON
+	prepared, err := e.prepareRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	sampled := prepared != nil && e.config.debugSampler != nil && e.config.debugSampler(request)
+	if sampled {
+		prepared.Options.EnableTrace = true
+	}
+	requestJSON, err := json.Marshal(prepared)
+	if err != nil {
 		return nil, err
 	}
 
+	// Call FFI function
+	body, err := call(requestJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	// Decode the error envelope and the response in a single pass, so the
+	// native response is read only once
+	var native nativeResponse
+	if err := json.NewDecoder(body).Decode(&native); err != nil {
+		if err != io.EOF {
+			return nil, err
+		}
+		if !e.config.lenientResponses {
+			return nil, ErrEmptyResponse
+		}
+		return &DecisionResponse{}, nil
+	}
+
+	if native.Error != "" {
+		decisionErr := &DecisionError{
+			Message:  native.Error,
+			Code:     native.ErrorCode,
+			sentinel: nativeErrorCodes[native.ErrorCode],
+		}
+		if native.Diagnostics != nil {
+			decisionErr.Kind = native.Diagnostics.ErrorKind
+		}
+		if native.RetryAfterMs > 0 {
+			decisionErr.RetryAfter = time.Duration(native.RetryAfterMs) * time.Millisecond
+		}
+		if e.config.diagnostics {
+			decisionErr.Diagnostics = native.Diagnostics
+			if decisionErr.Diagnostics == nil {
+				decisionErr.Diagnostics = &Diagnostics{}
+			}
+			decisionErr.Diagnostics.InputHash = inputHash(requestJSON)
+			if e.config.redaction != nil {
+				if redactedJSON, err := json.Marshal(e.config.redaction.apply(prepared)); err == nil {
+					decisionErr.Diagnostics.InputHash = inputHash(redactedJSON)
+				}
+			}
+		}
+		return nil, decisionErr
+	}
+
+	response := native.DecisionResponse
 	if response.Result.Signal != nil {
 		response.Decision = response.Result.Signal.Type
 	}
+	// Older native builds ignore first_action_only, so trim on the Go side too.
+	// Native actions keep the matched conclusion's declared order, so the first
+	// one is the highest-priority action without sorting.
+	if request != nil && request.Options.FirstActionOnly && len(response.Result.Actions) > 1 {
+		response.Result.Actions = response.Result.Actions[:1]
+	}
 	response.Actions = response.Result.Actions
 
+	if response.Decision == "" && !e.config.lenientResponses {
+		return nil, ErrEmptyResponse
+	}
+
+	if sampled {
+		e.config.logger.Info("sampled decision trace",
+			"request_id", response.RequestID,
+			"decision", response.Decision,
+			"event_data", e.config.redaction.apply(request).EventData,
+			"trace", e.config.redaction.applyTrace(response.Trace))
+		if !request.Options.EnableTrace {
+			response.Trace = nil
+		}
+	}
+
 	return &response, nil
 }
 
+// nativeResult copies and frees a response string returned by a native decide call
+func nativeResult(resultPtr *C.char) ([]byte, error) {
+	if resultPtr == nil {
+		return nil, errDecisionFailed
+	}
+	defer C.corint_string_free(resultPtr)
+	return C.GoBytes(unsafe.Pointer(resultPtr), C.int(C.strlen(resultPtr))), nil
+}
+
+// prepareRequest returns the request to send natively, applying engine-level
+// defaults to a shallow copy so the caller's request is left untouched
+func (e *DecisionEngine) prepareRequest(request *DecisionRequest) (*DecisionRequest, error) {
+	if request == nil {
+		return nil, nil
+	}
+
+	prepared := *request
+	if prepared.Options.MaxTraceBytes == 0 {
+		prepared.Options.MaxTraceBytes = e.config.maxTraceBytes
+	}
+	if err := sanitizeRequest(&prepared, e.config.numberPolicy); err != nil {
+		return nil, err
+	}
+	return &prepared, nil
+}
+
+// RequestCount returns the number of decisions requested since creation or the last reset
+func (e *DecisionEngine) RequestCount() uint64 {
+	return e.requests.Load()
+}
+
+// ResetRequestCount resets the decision request counter to zero
+func (e *DecisionEngine) ResetRequestCount() {
+	e.requests.Store(0)
+}
+
+// nativeErrorCodes maps native error codes to the sentinel errors they unwrap to
+var nativeErrorCodes = map[string]error{
+	"unknown_repository": ErrUnknownRepository,
+	"rule_not_found":     ErrRuleNotFound,
+}
+
 // Close closes the engine and frees resources
 func (e *DecisionEngine) Close() {
 	if e.handle != nil {
-		C.corint_engine_free(e.handle)
+		nativeEngineFree(e.handle)
 		e.handle = nil
 	}
 }
 
-// Version returns the CORINT version
+// nativeEngineFree frees a native engine handle; tests replace it to observe
+// when engines are closed
+var nativeEngineFree = func(handle unsafe.Pointer) {
+	C.corint_engine_free(handle)
+}
+
+// Version returns the CORINT version. It is safe for concurrent use: each call
+// receives its own native string, which is copied and freed before returning.
 func Version() string {
 	versionPtr := C.corint_version()
+	if versionPtr == nil {
+		return ""
+	}
 	defer C.corint_string_free(versionPtr)
 	return C.GoString(versionPtr)
 }
 
+// nativeLiveStrings returns the number of native strings not yet freed; tests
+// use it to check that wrappers free every string they receive
+var nativeLiveStrings = func() int {
+	return int(C.corint_live_strings())
+}
+
+// BuildInfo describes the native library build
+type BuildInfo struct {
+	Version       string   `json:"version"`
+	GitCommit     string   `json:"git_commit"`
+	BuildFeatures []string `json:"build_features"`
+	ABIVersion    int      `json:"abi_version"`
+	// Capabilities lists optional native features, such as "chunked_responses"
+	Capabilities []string `json:"capabilities"`
+}
+
+// VersionInfo returns build details of the native library; Version is the short form
+func VersionInfo() (BuildInfo, error) {
+	infoJSON, err := nativeVersionInfo()
+	if err != nil {
+		return BuildInfo{}, err
+	}
+
+	var info BuildInfo
+	if err := json.Unmarshal(infoJSON, &info); err != nil {
+		return BuildInfo{}, err
+	}
+	return info, nil
+}
+
+// nativeVersionInfo returns the native build details JSON; tests replace it to
+// fake native builds
+var nativeVersionInfo = func() ([]byte, error) {
+	infoPtr := C.corint_version_info()
+	if infoPtr == nil {
+		return nil, errors.New("failed to get native version info")
+	}
+	defer C.corint_string_free(infoPtr)
+	return []byte(C.GoString(infoPtr)), nil
+}
+
 // InitLogging initializes the logging system
 func InitLogging() {
 	C.corint_init_logging()
@@ -0,0 +1,352 @@
+package corint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// approveResponse is a minimal native response approving the request
+const approveResponse = `{"request_id":"req_1","result":{"signal":{"type":"approve"},"actions":[],"score":0,"triggered_rules":[],"explanation":"","context":{}},"processing_time_ms":1}`
+
+// testHandle backs the placeholder native handle of engines from newFakeEngine
+var testHandle byte
+
+// newFakeEngine returns an engine whose native decisions are answered by
+// native for the rest of the test. The engine must not be closed natively.
+func newFakeEngine(t *testing.T, native func(requestJSON []byte) ([]byte, error), opts ...EngineOption) *DecisionEngine {
+	t.Helper()
+	original := nativeDecide
+	nativeDecide = func(_ unsafe.Pointer, requestJSON []byte) ([]byte, error) {
+		return native(requestJSON)
+	}
+	t.Cleanup(func() { nativeDecide = original })
+	return &DecisionEngine{handle: unsafe.Pointer(&testHandle), config: newEngineConfig(opts)}
+}
+
+// respondWith returns a fake native decide call answering every request with response
+func respondWith(response string) func([]byte) ([]byte, error) {
+	return func([]byte) ([]byte, error) {
+		return []byte(response), nil
+	}
+}
+
+// stubEngine is an Engine answering with decide, reporting health as healthErr
+type stubEngine struct {
+	decide    func(request *DecisionRequest) (*DecisionResponse, error)
+	healthErr error
+}
+
+func (s *stubEngine) Decide(request *DecisionRequest) (*DecisionResponse, error) {
+	return s.decide(request)
+}
+
+func (s *stubEngine) HealthCheck() error {
+	return s.healthErr
+}
+
+// answering returns a stubEngine deciding every request as decision
+func answering(decision string) *stubEngine {
+	return &stubEngine{decide: func(*DecisionRequest) (*DecisionResponse, error) {
+		return decided(decision), nil
+	}}
+}
+
+// eventRequest returns a request carrying a minimal event
+func eventRequest() *DecisionRequest {
+	return &DecisionRequest{EventData: map[string]interface{}{"type": "login"}}
+}
+
+func TestDecideEmptyNativeResponse(t *testing.T) {
+	for _, native := range []string{"", "{}", `{"result":{}}`} {
+		t.Run("strict "+native, func(t *testing.T) {
+			engine := newFakeEngine(t, respondWith(native))
+			response, err := engine.Decide(eventRequest())
+			if !errors.Is(err, ErrEmptyResponse) {
+				t.Fatalf("Decide() = %v, %v; want ErrEmptyResponse", response, err)
+			}
+		})
+
+		t.Run("lenient "+native, func(t *testing.T) {
+			engine := newFakeEngine(t, respondWith(native), WithLenientResponses())
+			response, err := engine.Decide(eventRequest())
+			if err != nil {
+				t.Fatalf("Decide: %v", err)
+			}
+			if response == nil || response.Decision != "" {
+				t.Fatalf("Decide() = %+v, want a response with an empty decision", response)
+			}
+		})
+	}
+}
+
+func TestDecideDecodesDecision(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse))
+	response, err := engine.Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if response.Decision != "approve" || response.RequestID != "req_1" {
+		t.Fatalf("Decide() = %+v, want an approval for req_1", response)
+	}
+}
+
+func TestDecideMaxEventAge(t *testing.T) {
+	calls := 0
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		calls++
+		return []byte(approveResponse), nil
+	}, WithMaxEventAge(time.Minute))
+
+	stale := eventRequest()
+	staleTime := time.Now().Add(-time.Hour)
+	stale.EventTime = &staleTime
+	if _, err := engine.Decide(stale); !errors.Is(err, ErrStaleEvent) {
+		t.Fatalf("stale event: Decide() error = %v, want ErrStaleEvent", err)
+	}
+	if calls != 0 {
+		t.Fatal("stale event reached the native engine")
+	}
+
+	fresh := eventRequest()
+	freshTime := time.Now().Add(-time.Second)
+	fresh.EventTime = &freshTime
+	if _, err := engine.Decide(fresh); err != nil {
+		t.Fatalf("fresh event: %v", err)
+	}
+
+	if _, err := engine.Decide(eventRequest()); err != nil {
+		t.Fatalf("event without EventTime: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("native engine called %d times, want 2", calls)
+	}
+}
+
+func TestDecideEventTimeNotSentNatively(t *testing.T) {
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		if strings.Contains(string(requestJSON), "event_time") {
+			t.Errorf("EventTime was sent natively: %s", requestJSON)
+		}
+		return []byte(approveResponse), nil
+	}, WithMaxEventAge(time.Minute))
+
+	request := eventRequest()
+	now := time.Now()
+	request.EventTime = &now
+	if _, err := engine.Decide(request); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+}
+
+func TestRequestCountConcurrent(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse))
+
+	const workers, perWorker = 16, 50
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if _, err := engine.Decide(eventRequest()); err != nil {
+					t.Errorf("Decide: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := engine.RequestCount(); got != workers*perWorker {
+		t.Fatalf("RequestCount() = %d, want %d", got, workers*perWorker)
+	}
+
+	engine.ResetRequestCount()
+	if got := engine.RequestCount(); got != 0 {
+		t.Fatalf("RequestCount() after reset = %d, want 0", got)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.Decide(eventRequest())
+		}()
+	}
+	wg.Wait()
+	if got := engine.RequestCount(); got != workers {
+		t.Fatalf("RequestCount() after reset and %d decisions = %d", workers, got)
+	}
+}
+
+func TestDecideFirstActionOnly(t *testing.T) {
+	native := `{"request_id":"req_1","result":{"signal":{"type":"decline"},"actions":["BLOCK","NOTIFY","REVIEW"],"score":90,"triggered_rules":[],"explanation":"","context":{}},"processing_time_ms":1}`
+	engine := newFakeEngine(t, respondWith(native))
+
+	request := eventRequest()
+	request.Options.FirstActionOnly = true
+	response, err := engine.Decide(request)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if len(response.Actions) != 1 || response.Actions[0] != "BLOCK" {
+		t.Errorf("Actions = %v, want [BLOCK]", response.Actions)
+	}
+	if len(response.Result.Actions) != 1 {
+		t.Errorf("Result.Actions = %v, want one action", response.Result.Actions)
+	}
+
+	response, err = engine.Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if len(response.Actions) != 3 {
+		t.Errorf("Actions = %v, want all three without FirstActionOnly", response.Actions)
+	}
+}
+
+func TestDecideNamedOutputs(t *testing.T) {
+	native := `{"request_id":"req_1","result":{"signal":{"type":"review"},"actions":[],"score":50,"triggered_rules":[],"explanation":"","context":{},"outputs":{"risk_report":{"level":"medium","factors":["new_device"]},"case_id":"case-7"}},"processing_time_ms":1}`
+	response, err := newFakeEngine(t, respondWith(native)).Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	raw, ok := response.Output("risk_report")
+	if !ok {
+		t.Fatal("Output(risk_report) not found")
+	}
+	var report struct {
+		Level   string   `json:"level"`
+		Factors []string `json:"factors"`
+	}
+	if err := json.Unmarshal(raw, &report); err != nil {
+		t.Fatalf("decode risk_report: %v", err)
+	}
+	if report.Level != "medium" || len(report.Factors) != 1 || report.Factors[0] != "new_device" {
+		t.Errorf("risk_report = %+v", report)
+	}
+
+	if raw, ok := response.Output("case_id"); !ok || string(raw) != `"case-7"` {
+		t.Errorf("Output(case_id) = %s, %v", raw, ok)
+	}
+	if _, ok := response.Output("missing"); ok {
+		t.Error("Output(missing) reported an artifact")
+	}
+}
+
+func TestDecideSendsEvaluationTime(t *testing.T) {
+	var options []map[string]interface{}
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		var sent struct {
+			Options map[string]interface{} `json:"options"`
+		}
+		if err := json.Unmarshal(requestJSON, &sent); err != nil {
+			return nil, err
+		}
+		options = append(options, sent.Options)
+		return []byte(approveResponse), nil
+	})
+
+	at := time.Date(2024, 3, 1, 23, 30, 0, 0, time.FixedZone("CET", 3600))
+	request := eventRequest()
+	request.Options.EvaluationTime = &at
+	if _, err := engine.Decide(request); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if _, err := engine.Decide(eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if got := options[0]["eval_time"]; got != "2024-03-01T23:30:00+01:00" {
+		t.Errorf("eval_time = %v, want RFC 3339 with offset", got)
+	}
+	if _, ok := options[1]["eval_time"]; ok {
+		t.Errorf("eval_time sent without EvaluationTime: %v", options[1])
+	}
+}
+
+func TestDecideSubDecisions(t *testing.T) {
+	native := `{"request_id":"req_1","result":{"signal":{"type":"approve"},"actions":[],"score":10,"triggered_rules":[],"explanation":"","context":{}},"decisions":{"compliance":{"signal":{"type":"review"},"actions":["KYC"],"score":70,"triggered_rules":["sanctions_match"],"explanation":"","context":{}},"pending":{"signal":null,"actions":[],"score":0,"triggered_rules":[],"explanation":"","context":{}}},"processing_time_ms":1}`
+	response, err := newFakeEngine(t, respondWith(native)).Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if decision, ok := response.SubDecision("compliance"); !ok || decision != "review" {
+		t.Errorf("SubDecision(compliance) = %q, %v; want review", decision, ok)
+	}
+	if compliance := response.Decisions["compliance"]; compliance.Score != 70 || compliance.Actions[0] != "KYC" {
+		t.Errorf("Decisions[compliance] = %+v", compliance)
+	}
+	if decision, ok := response.SubDecision("pending"); !ok || decision != "" {
+		t.Errorf("SubDecision(pending) = %q, %v; want an empty signal", decision, ok)
+	}
+	if _, ok := response.SubDecision("missing"); ok {
+		t.Error("SubDecision(missing) reported a sub-decision")
+	}
+}
+
+// tenantKey carries the calling tenant in a decision context
+type tenantKey struct{}
+
+// tenantAuthorizer rejects decisions whose context carries the "blocked" tenant
+func tenantAuthorizer(ctx context.Context, _ *DecisionRequest) error {
+	if tenant, _ := ctx.Value(tenantKey{}).(string); tenant == "blocked" {
+		return errors.New("tenant blocked")
+	}
+	return nil
+}
+
+func TestDecideContextAuthorizer(t *testing.T) {
+	var calls int
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		calls++
+		return []byte(approveResponse), nil
+	}, WithAuthorizer(tenantAuthorizer))
+
+	permitted := context.WithValue(context.Background(), tenantKey{}, "acme")
+	if response, err := engine.DecideContext(permitted, eventRequest()); err != nil || response.Decision != "approve" {
+		t.Fatalf("permitted DecideContext() = %v, %v", response, err)
+	}
+
+	blocked := context.WithValue(context.Background(), tenantKey{}, "blocked")
+	if _, err := engine.DecideContext(blocked, eventRequest()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("blocked DecideContext() error = %v, want ErrUnauthorized", err)
+	}
+	if calls != 1 {
+		t.Errorf("native engine called %d times, want only the permitted decision", calls)
+	}
+}
+
+func TestDecideEmptyEventData(t *testing.T) {
+	var calls int
+	native := func([]byte) ([]byte, error) {
+		calls++
+		return []byte(approveResponse), nil
+	}
+
+	strict := newFakeEngine(t, native)
+	for _, request := range []*DecisionRequest{nil, {}, {EventData: map[string]interface{}{}}} {
+		if _, err := strict.Decide(request); !errors.Is(err, ErrEmptyEventData) {
+			t.Errorf("Decide(%+v) error = %v, want ErrEmptyEventData", request, err)
+		}
+	}
+
+	lenient := newFakeEngine(t, native, WithEmptyEventDecision("review"))
+	response, err := lenient.Decide(&DecisionRequest{})
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if response.Decision != "review" || response.Result.Signal == nil || response.Result.Signal.Type != "review" {
+		t.Errorf("response = %+v, want the configured review decision", response)
+	}
+	if calls != 0 {
+		t.Errorf("native engine called %d times for empty events", calls)
+	}
+}
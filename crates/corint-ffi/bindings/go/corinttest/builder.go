@@ -0,0 +1,85 @@
+// Package corinttest provides helpers for testing code that uses the CORINT Go binding
+package corinttest
+
+import (
+	corint "github.com/corint/corint-go"
+)
+
+// ResponseBuilder builds DecisionResponse values for tests
+type ResponseBuilder struct {
+	response corint.DecisionResponse
+}
+
+// NewResponseBuilder creates a builder for an empty decision response
+func NewResponseBuilder() *ResponseBuilder {
+	return &ResponseBuilder{}
+}
+
+// RequestID sets the response request ID
+func (b *ResponseBuilder) RequestID(id string) *ResponseBuilder {
+	b.response.RequestID = id
+	return b
+}
+
+// Decision sets the decision signal type (e.g. "approve", "decline", "review")
+func (b *ResponseBuilder) Decision(decision string) *ResponseBuilder {
+	b.response.Result.Signal = &corint.DecisionSignal{Type: decision}
+	b.response.Decision = decision
+	return b
+}
+
+// Action appends an action to the response
+func (b *ResponseBuilder) Action(action string) *ResponseBuilder {
+	b.response.Result.Actions = append(b.response.Result.Actions, action)
+	b.response.Actions = b.response.Result.Actions
+	return b
+}
+
+// Score sets the total risk score
+func (b *ResponseBuilder) Score(score int) *ResponseBuilder {
+	b.response.Result.Score = score
+	return b
+}
+
+// TriggeredRule appends a triggered rule ID
+func (b *ResponseBuilder) TriggeredRule(ruleID string) *ResponseBuilder {
+	b.response.Result.TriggeredRules = append(b.response.Result.TriggeredRules, ruleID)
+	return b
+}
+
+// Metadata sets a metadata entry
+func (b *ResponseBuilder) Metadata(key, value string) *ResponseBuilder {
+	if b.response.Metadata == nil {
+		b.response.Metadata = make(map[string]string)
+	}
+	b.response.Metadata[key] = value
+	return b
+}
+
+// Trace sets the execution trace
+func (b *ResponseBuilder) Trace(trace map[string]interface{}) *ResponseBuilder {
+	b.response.Trace = trace
+	return b
+}
+
+// Build returns a copy of the response built so far
+func (b *ResponseBuilder) Build() *corint.DecisionResponse {
+	response := b.response
+
+	if b.response.Result.Signal != nil {
+		signal := *b.response.Result.Signal
+		response.Result.Signal = &signal
+	}
+	response.Result.Actions = append([]string(nil), b.response.Result.Actions...)
+	response.Result.TriggeredRules = append([]string(nil), b.response.Result.TriggeredRules...)
+	response.Actions = response.Result.Actions
+	if b.response.Metadata != nil {
+		response.Metadata = make(map[string]string, len(b.response.Metadata))
+		for k, v := range b.response.Metadata {
+			response.Metadata[k] = v
+		}
+	}
+	response.Trace = b.response.Trace.Clone()
+
+	return &response
+}
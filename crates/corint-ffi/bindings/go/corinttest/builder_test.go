@@ -0,0 +1,90 @@
+package corinttest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corint "github.com/corint/corint-go"
+)
+
+func TestResponseBuilder(t *testing.T) {
+	response := NewResponseBuilder().
+		RequestID("req_1").
+		Decision("review").
+		Action("OTP").
+		Action("KYC").
+		Score(85).
+		TriggeredRule("new_device").
+		Metadata("tenant_id", "acme").
+		Trace(map[string]interface{}{"pipeline": map[string]interface{}{"pipeline_id": "login"}}).
+		Build()
+
+	if response.RequestID != "req_1" {
+		t.Errorf("RequestID = %q, want req_1", response.RequestID)
+	}
+	if response.Decision != "review" || response.Result.Signal == nil || response.Result.Signal.Type != "review" {
+		t.Errorf("Decision = %q, Signal = %+v, want review", response.Decision, response.Result.Signal)
+	}
+	if want := []string{"OTP", "KYC"}; !reflect.DeepEqual(response.Actions, want) || !reflect.DeepEqual(response.Result.Actions, want) {
+		t.Errorf("Actions = %v, Result.Actions = %v, want %v", response.Actions, response.Result.Actions, want)
+	}
+	if response.Result.Score != 85 {
+		t.Errorf("Score = %d, want 85", response.Result.Score)
+	}
+	if want := []string{"new_device"}; !reflect.DeepEqual(response.Result.TriggeredRules, want) {
+		t.Errorf("TriggeredRules = %v, want %v", response.Result.TriggeredRules, want)
+	}
+	if response.Metadata["tenant_id"] != "acme" {
+		t.Errorf("Metadata = %v, want tenant_id=acme", response.Metadata)
+	}
+	if _, ok := response.Trace["pipeline"]; !ok {
+		t.Errorf("Trace = %v, want a pipeline section", response.Trace)
+	}
+}
+
+func TestResponseBuilderJSONRoundTrip(t *testing.T) {
+	built := NewResponseBuilder().RequestID("req_2").Decision("decline").Action("BLOCK").Action("NOTIFY").Build()
+
+	data, err := json.Marshal(built)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded corint.DecisionResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if decoded.Result.Signal == nil || decoded.Result.Signal.Type != "decline" {
+		t.Errorf("decoded signal = %+v, want decline", decoded.Result.Signal)
+	}
+	if want := []string{"BLOCK", "NOTIFY"}; !reflect.DeepEqual(decoded.Result.Actions, want) {
+		t.Errorf("decoded actions = %v, want %v", decoded.Result.Actions, want)
+	}
+}
+
+func TestResponseBuilderBuildIsIndependent(t *testing.T) {
+	trace := map[string]interface{}{"pipeline": map[string]interface{}{"pipeline_id": "login"}}
+	builder := NewResponseBuilder().Decision("approve").Action("A").Metadata("k", "v").Trace(trace)
+	first := builder.Build()
+
+	builder.Decision("decline").Action("B").Metadata("k", "changed")
+	trace["extra"] = true
+	trace["pipeline"].(map[string]interface{})["pipeline_id"] = "changed"
+
+	if first.Decision != "approve" || first.Result.Signal.Type != "approve" {
+		t.Errorf("first decision changed to %q", first.Decision)
+	}
+	if !reflect.DeepEqual(first.Actions, []string{"A"}) {
+		t.Errorf("first actions changed to %v", first.Actions)
+	}
+	if first.Metadata["k"] != "v" {
+		t.Errorf("first metadata changed to %v", first.Metadata)
+	}
+	if _, ok := first.Trace["extra"]; ok {
+		t.Error("first trace gained a key added after Build")
+	}
+	if id := first.Trace["pipeline"].(map[string]interface{})["pipeline_id"]; id != "login" {
+		t.Errorf("first trace pipeline_id changed to %v", id)
+	}
+}
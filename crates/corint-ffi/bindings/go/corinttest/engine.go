@@ -0,0 +1,57 @@
+package corinttest
+
+import (
+	corint "github.com/corint/corint-go"
+)
+
+// PredicateRule is a Go-defined rule evaluated by InMemoryEngine
+type PredicateRule struct {
+	// ID is reported in the response's triggered rules when the rule matches
+	ID string
+	// Evaluate inspects the request's event data and returns the decision and
+	// actions for a match, or an empty decision when the rule does not match
+	Evaluate func(eventData map[string]interface{}) (decision string, actions []string)
+}
+
+// InMemoryEngine evaluates predicate rules in Go and satisfies corint.Engine,
+// so rule-driven code can be exercised without loading a rule repository.
+// Every matching rule is triggered and contributes its actions; the first
+// matching rule decides. Requests matching no rule get DefaultDecision.
+type InMemoryEngine struct {
+	DefaultDecision string
+	Rules           []PredicateRule
+}
+
+// NewInMemoryEngine creates an engine that approves requests matching none of rules
+func NewInMemoryEngine(rules ...PredicateRule) *InMemoryEngine {
+	return &InMemoryEngine{DefaultDecision: "approve", Rules: rules}
+}
+
+// Decide evaluates the rules against the request's event data in order
+func (e *InMemoryEngine) Decide(request *corint.DecisionRequest) (*corint.DecisionResponse, error) {
+	var eventData map[string]interface{}
+	if request != nil {
+		eventData = request.EventData
+	}
+
+	builder := NewResponseBuilder()
+	decision := ""
+	for _, rule := range e.Rules {
+		ruleDecision, actions := rule.Evaluate(eventData)
+		if ruleDecision == "" {
+			continue
+		}
+		if decision == "" {
+			decision = ruleDecision
+		}
+		builder.TriggeredRule(rule.ID)
+		for _, action := range actions {
+			builder.Action(action)
+		}
+	}
+	if decision == "" {
+		decision = e.DefaultDecision
+	}
+
+	return builder.Decision(decision).Build(), nil
+}
@@ -0,0 +1,64 @@
+package corinttest
+
+import (
+	"reflect"
+	"testing"
+
+	corint "github.com/corint/corint-go"
+)
+
+// amountOver returns a rule declining events with an amount above limit
+func amountOver(id string, limit float64, decision string, actions ...string) PredicateRule {
+	return PredicateRule{ID: id, Evaluate: func(eventData map[string]interface{}) (string, []string) {
+		if amount, _ := eventData["amount"].(float64); amount > limit {
+			return decision, actions
+		}
+		return "", nil
+	}}
+}
+
+func TestInMemoryEngine(t *testing.T) {
+	engine := NewInMemoryEngine(
+		amountOver("very_high_amount", 10000, "decline", "BLOCK"),
+		amountOver("high_amount", 1000, "review", "OTP"),
+	)
+	var _ corint.Engine = engine
+
+	tests := []struct {
+		amount    float64
+		decision  string
+		actions   []string
+		triggered []string
+	}{
+		{50, "approve", nil, nil},
+		{5000, "review", []string{"OTP"}, []string{"high_amount"}},
+		{20000, "decline", []string{"BLOCK", "OTP"}, []string{"very_high_amount", "high_amount"}},
+	}
+
+	for _, tt := range tests {
+		response, err := engine.Decide(&corint.DecisionRequest{EventData: map[string]interface{}{"amount": tt.amount}})
+		if err != nil {
+			t.Fatalf("Decide(%v): %v", tt.amount, err)
+		}
+		if response.Decision != tt.decision {
+			t.Errorf("Decide(%v).Decision = %q, want %q", tt.amount, response.Decision, tt.decision)
+		}
+		if len(response.Actions) != len(tt.actions) || (len(tt.actions) > 0 && !reflect.DeepEqual(response.Actions, tt.actions)) {
+			t.Errorf("Decide(%v).Actions = %v, want %v", tt.amount, response.Actions, tt.actions)
+		}
+		if len(response.Result.TriggeredRules) != len(tt.triggered) || (len(tt.triggered) > 0 && !reflect.DeepEqual(response.Result.TriggeredRules, tt.triggered)) {
+			t.Errorf("Decide(%v).TriggeredRules = %v, want %v", tt.amount, response.Result.TriggeredRules, tt.triggered)
+		}
+	}
+}
+
+func TestInMemoryEngineDefaultDecision(t *testing.T) {
+	engine := &InMemoryEngine{DefaultDecision: "review"}
+	response, err := engine.Decide(nil)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if response.Decision != "review" {
+		t.Errorf("Decision = %q, want the default review", response.Decision)
+	}
+}
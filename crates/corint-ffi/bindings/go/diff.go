@@ -0,0 +1,101 @@
+package corint
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// FieldChangeKind classifies a FieldChange
+type FieldChangeKind string
+
+const (
+	FieldAdded   FieldChangeKind = "added"
+	FieldRemoved FieldChangeKind = "removed"
+	FieldChanged FieldChangeKind = "changed"
+)
+
+// FieldChange is a single field that differs between two requests
+type FieldChange struct {
+	// Path is the dotted field path, e.g. "event_data.user.country"
+	Path string
+	Kind FieldChangeKind
+	Old  interface{}
+	New  interface{}
+}
+
+// RequestDiff lists the field changes between two requests ordered by path
+type RequestDiff struct {
+	Changes []FieldChange
+}
+
+// Empty reports whether the requests had no differing fields
+func (d RequestDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// DiffRequests reports the EventData, Features and Vars fields that differ
+// from a to b. Values are compared in their JSON form, so an int and a float64
+// with the same value are equal; nested maps are compared field by field.
+func DiffRequests(a, b *DecisionRequest) RequestDiff {
+	if a == nil {
+		a = &DecisionRequest{}
+	}
+	if b == nil {
+		b = &DecisionRequest{}
+	}
+
+	var diff RequestDiff
+	diffMaps(&diff, "event_data", canonicalMap(a.EventData), canonicalMap(b.EventData))
+	diffMaps(&diff, "features", canonicalMap(a.Features), canonicalMap(b.Features))
+	diffMaps(&diff, "vars", canonicalMap(a.Vars), canonicalMap(b.Vars))
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Path < diff.Changes[j].Path
+	})
+	return diff
+}
+
+// canonicalMap round-trips m through JSON so values compare by their encoded form
+func canonicalMap(m map[string]interface{}) map[string]interface{} {
+	if len(m) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	var canonical map[string]interface{}
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		return m
+	}
+	return canonical
+}
+
+// diffMaps appends the changes from a to b under prefix
+func diffMaps(diff *RequestDiff, prefix string, a, b map[string]interface{}) {
+	for key, oldValue := range a {
+		path := prefix + "." + key
+		newValue, ok := b[key]
+		if !ok {
+			diff.Changes = append(diff.Changes, FieldChange{Path: path, Kind: FieldRemoved, Old: oldValue})
+			continue
+		}
+
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffMaps(diff, path, oldMap, newMap)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diff.Changes = append(diff.Changes, FieldChange{Path: path, Kind: FieldChanged, Old: oldValue, New: newValue})
+		}
+	}
+
+	for key, newValue := range b {
+		if _, ok := a[key]; !ok {
+			diff.Changes = append(diff.Changes, FieldChange{Path: prefix + "." + key, Kind: FieldAdded, New: newValue})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package corint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRequests(t *testing.T) {
+	a := &DecisionRequest{
+		EventData: map[string]interface{}{
+			"amount":  100,
+			"country": "US",
+			"user":    map[string]interface{}{"id": "u1", "email": "a@example.com"},
+		},
+		Features: map[string]interface{}{"txn_count_24h": 3},
+	}
+	b := &DecisionRequest{
+		EventData: map[string]interface{}{
+			"amount": 100.0,
+			"user":   map[string]interface{}{"id": "u1", "email": "b@example.com"},
+			"device": "ios",
+		},
+		Features: map[string]interface{}{"txn_count_24h": 3},
+		Vars:     map[string]interface{}{"threshold": 50},
+	}
+
+	diff := DiffRequests(a, b)
+	want := []FieldChange{
+		{Path: "event_data.country", Kind: FieldRemoved, Old: "US"},
+		{Path: "event_data.device", Kind: FieldAdded, New: "ios"},
+		{Path: "event_data.user.email", Kind: FieldChanged, Old: "a@example.com", New: "b@example.com"},
+		{Path: "vars.threshold", Kind: FieldAdded, New: float64(50)},
+	}
+	if !reflect.DeepEqual(diff.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", diff.Changes, want)
+	}
+	if diff.Empty() {
+		t.Error("Empty() = true for differing requests")
+	}
+}
+
+func TestDiffRequestsEqual(t *testing.T) {
+	request := eventRequest()
+	if diff := DiffRequests(request, eventRequest()); !diff.Empty() {
+		t.Errorf("Changes = %+v, want none", diff.Changes)
+	}
+	if diff := DiffRequests(nil, &DecisionRequest{EventData: map[string]interface{}{}}); !diff.Empty() {
+		t.Errorf("nil vs empty Changes = %+v, want none", diff.Changes)
+	}
+}
+
+func TestDiffRequestsNestedTypeChange(t *testing.T) {
+	a := &DecisionRequest{EventData: map[string]interface{}{"user": map[string]interface{}{"id": "u1"}}}
+	b := &DecisionRequest{EventData: map[string]interface{}{"user": "u1"}}
+
+	diff := DiffRequests(a, b)
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "event_data.user" || diff.Changes[0].Kind != FieldChanged {
+		t.Errorf("Changes = %+v, want a single change of event_data.user", diff.Changes)
+	}
+}
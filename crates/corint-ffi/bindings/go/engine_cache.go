@@ -0,0 +1,192 @@
+package corint
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+)
+
+// ErrEngineCacheClosed is returned when using an EngineCache after Close
+var ErrEngineCacheClosed = errors.New("engine cache is closed")
+
+// EngineCacheStats reports the activity of an EngineCache
+type EngineCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// cachedEngine is an EngineCache entry. refs counts in-flight users; an
+// evicted engine is closed once the last of them releases it.
+type cachedEngine struct {
+	key     string
+	engine  *DecisionEngine
+	refs    int
+	pinned  bool
+	evicted bool
+}
+
+// EngineCache keeps up to a fixed number of engines, such as one per tenant,
+// loading them on demand and evicting the least recently used. Pinned engines
+// are never evicted, so the cache may exceed its capacity while pins are held.
+type EngineCache struct {
+	capacity int
+	load     func(key string) (*DecisionEngine, error)
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	stats   EngineCacheStats
+	closed  bool
+}
+
+// NewEngineCache creates a cache holding up to capacity engines created by load
+func NewEngineCache(capacity int, load func(key string) (*DecisionEngine, error)) *EngineCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &EngineCache{
+		capacity: capacity,
+		load:     load,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Decide executes request on the engine for key, loading it if needed. An
+// engine evicted while deciding is closed only after the decision finishes.
+func (c *EngineCache) Decide(key string, request *DecisionRequest) (*DecisionResponse, error) {
+	entry, err := c.acquire(key)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(entry)
+	return entry.engine.Decide(request)
+}
+
+// Pin loads the engine for key if needed and exempts it from eviction
+func (c *EngineCache) Pin(key string) error {
+	entry, err := c.acquire(key)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	entry.pinned = !entry.evicted
+	c.mu.Unlock()
+	c.release(entry)
+	return nil
+}
+
+// Unpin makes the engine for key evictable again
+func (c *EngineCache) Unpin(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cachedEngine).pinned = false
+		c.evictLocked(nil)
+	}
+}
+
+// Len returns the number of engines currently cached
+func (c *EngineCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Stats returns the hit, miss and eviction counts since creation
+func (c *EngineCache) Stats() EngineCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Close evicts every engine. Engines still deciding are closed when they finish.
+func (c *EngineCache) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for c.order.Len() > 0 {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+// acquire returns the entry for key with a reference held, loading it on a miss
+func (c *EngineCache) acquire(key string) (*cachedEngine, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrEngineCacheClosed
+	}
+	if element, ok := c.entries[key]; ok {
+		c.stats.Hits++
+		c.order.MoveToFront(element)
+		entry := element.Value.(*cachedEngine)
+		entry.refs++
+		c.mu.Unlock()
+		return entry, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	engine, err := c.load(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		engine.Close()
+		return nil, ErrEngineCacheClosed
+	}
+	// Another caller may have loaded the same key meanwhile
+	if element, ok := c.entries[key]; ok {
+		engine.Close()
+		c.order.MoveToFront(element)
+		entry := element.Value.(*cachedEngine)
+		entry.refs++
+		return entry, nil
+	}
+
+	entry := &cachedEngine{key: key, engine: engine, refs: 1}
+	element := c.order.PushFront(entry)
+	c.entries[key] = element
+	c.evictLocked(element)
+	return entry, nil
+}
+
+// release drops a reference, closing the engine if it was evicted meanwhile
+func (c *EngineCache) release(entry *cachedEngine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.engine.Close()
+	}
+}
+
+// evictLocked removes least recently used unpinned engines until the cache is
+// within capacity. keep, if set, is never evicted.
+func (c *EngineCache) evictLocked(keep *list.Element) {
+	for element := c.order.Back(); element != nil && len(c.entries) > c.capacity; {
+		previous := element.Prev()
+		if element != keep && !element.Value.(*cachedEngine).pinned {
+			c.removeLocked(element)
+			c.stats.Evictions++
+		}
+		element = previous
+	}
+}
+
+// removeLocked drops element from the cache and closes its engine unless it is in use
+func (c *EngineCache) removeLocked(element *list.Element) {
+	entry := element.Value.(*cachedEngine)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	entry.evicted = true
+	entry.pinned = false
+	if entry.refs == 0 {
+		entry.engine.Close()
+	}
+}
@@ -0,0 +1,134 @@
+package corint
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+// fakeEngineLoader returns an EngineCache loader creating fake engines, and a
+// function reporting whether the engine loaded for a key has been freed
+func fakeEngineLoader(t *testing.T) (load func(string) (*DecisionEngine, error), freed func(key string) bool) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		handles = make(map[unsafe.Pointer]string)
+		closed  = make(map[string]bool)
+	)
+	original := nativeEngineFree
+	nativeEngineFree = func(handle unsafe.Pointer) {
+		mu.Lock()
+		defer mu.Unlock()
+		closed[handles[handle]] = true
+	}
+	t.Cleanup(func() { nativeEngineFree = original })
+
+	load = func(key string) (*DecisionEngine, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		handle := unsafe.Pointer(new(byte))
+		handles[handle] = key
+		return &DecisionEngine{handle: handle, config: newEngineConfig(nil)}, nil
+	}
+	freed = func(key string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return closed[key]
+	}
+	return load, freed
+}
+
+func TestEngineCachePinSurvivesEviction(t *testing.T) {
+	newFakeEngine(t, respondWith(approveResponse))
+	load, freed := fakeEngineLoader(t)
+	cache := NewEngineCache(2, load)
+	defer cache.Close()
+
+	if err := cache.Pin("tenant-a"); err != nil {
+		t.Fatalf("Pin: %v", err)
+	}
+	for _, key := range []string{"tenant-b", "tenant-c", "tenant-d"} {
+		if _, err := cache.Decide(key, eventRequest()); err != nil {
+			t.Fatalf("Decide(%s): %v", key, err)
+		}
+	}
+
+	if freed("tenant-a") {
+		t.Error("pinned engine was evicted")
+	}
+	if !freed("tenant-b") || !freed("tenant-c") || freed("tenant-d") {
+		t.Errorf("freed b=%v c=%v d=%v, want the least recently used unpinned engines freed",
+			freed("tenant-b"), freed("tenant-c"), freed("tenant-d"))
+	}
+	if stats := cache.Stats(); stats.Evictions != 2 || stats.Misses != 4 {
+		t.Errorf("Stats() = %+v, want 2 evictions and 4 misses", stats)
+	}
+
+	cache.Unpin("tenant-a")
+	if _, err := cache.Decide("tenant-e", eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if !freed("tenant-a") {
+		t.Error("unpinned engine was not evicted")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want capacity 2", cache.Len())
+	}
+}
+
+func TestEngineCacheClosesEvictedEngineAfterDecision(t *testing.T) {
+	load, freed := fakeEngineLoader(t)
+	started, finish := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	newFakeEngine(t, func([]byte) ([]byte, error) {
+		// Only the first decision blocks; sync.Once would block the others too
+		if calls.Add(1) == 1 {
+			close(started)
+			<-finish
+		}
+		return []byte(approveResponse), nil
+	})
+	cache := NewEngineCache(1, load)
+	defer cache.Close()
+
+	done := make(chan error)
+	go func() {
+		_, err := cache.Decide("tenant-slow", eventRequest())
+		done <- err
+	}()
+	<-started
+
+	if _, err := cache.Decide("tenant-other", eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if freed("tenant-slow") {
+		t.Fatal("engine freed while its decision was running")
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if !freed("tenant-slow") {
+		t.Error("evicted engine was not freed once its decision finished")
+	}
+}
+
+func TestEngineCacheClosed(t *testing.T) {
+	load, freed := fakeEngineLoader(t)
+	newFakeEngine(t, respondWith(approveResponse))
+	cache := NewEngineCache(2, load)
+	if _, err := cache.Decide("tenant-a", eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	cache.Close()
+	if !freed("tenant-a") {
+		t.Error("Close did not free cached engines")
+	}
+	if _, err := cache.Decide("tenant-a", eventRequest()); !errors.Is(err, ErrEngineCacheClosed) {
+		t.Errorf("Decide() after Close = %v, want ErrEngineCacheClosed", err)
+	}
+}
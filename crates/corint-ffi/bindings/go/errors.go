@@ -0,0 +1,59 @@
+package corint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrEngineClosed is returned when using an engine after Close
+var ErrEngineClosed = errors.New("engine has been closed")
+
+// DecisionError is returned when the native engine reports a failed decision
+type DecisionError struct {
+	// Message is the native error message
+	Message string
+	// Code is the machine-readable native error code, if any. Codes with a
+	// matching sentinel error, such as "unknown_repository", make errors.Is
+	// report that sentinel.
+	Code string
+	// Kind is the native error category, e.g. "runtime" or "compile". It is
+	// set whenever the native engine reports one, with or without
+	// WithDiagnostics, and is informational only: errors.Is does not use it.
+	Kind string
+	// Diagnostics is set when the engine was created with WithDiagnostics
+	Diagnostics *Diagnostics
+	// RetryAfter is the native hint for how long to wait before retrying, if any
+	RetryAfter time.Duration
+
+	// sentinel is the exported error matching Code, returned by Unwrap
+	sentinel error
+}
+
+// Diagnostics describes the context of a failed decision
+type Diagnostics struct {
+	// ErrorKind is the native error category, e.g. "runtime" or "compile"
+	ErrorKind string `json:"error_kind,omitempty"`
+	// NativeVersion is the version of the native library that failed
+	NativeVersion string `json:"native_version,omitempty"`
+	// InputHash is the hex SHA-256 of the request JSON sent to the native
+	// engine, or of its redacted form when the engine uses WithRedaction
+	InputHash string `json:"input_hash,omitempty"`
+}
+
+// Error returns the native error message
+func (e *DecisionError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the sentinel error matching Code, such as ErrUnknownRepository
+func (e *DecisionError) Unwrap() error {
+	return e.sentinel
+}
+
+// inputHash returns the hex SHA-256 digest of a serialized request
+func inputHash(requestJSON []byte) string {
+	sum := sha256.Sum256(requestJSON)
+	return hex.EncodeToString(sum[:])
+}
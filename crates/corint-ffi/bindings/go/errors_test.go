@@ -0,0 +1,58 @@
+package corint
+
+import (
+	"errors"
+	"testing"
+)
+
+// compileFailure is a native error response carrying diagnostics
+const compileFailure = `{"error":"rule high_amount failed to compile","success":false,"diagnostics":{"error_kind":"compile","native_version":"0.1.0"}}`
+
+func TestDecideDiagnostics(t *testing.T) {
+	var sent []byte
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		sent = requestJSON
+		return []byte(compileFailure), nil
+	}, WithDiagnostics())
+
+	_, err := engine.Decide(eventRequest())
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) {
+		t.Fatalf("Decide() error = %v, want a DecisionError", err)
+	}
+	if decisionErr.Message != "rule high_amount failed to compile" {
+		t.Errorf("Message = %q", decisionErr.Message)
+	}
+
+	want := Diagnostics{
+		ErrorKind:     "compile",
+		NativeVersion: "0.1.0",
+		InputHash:     inputHash(sent),
+	}
+	if decisionErr.Diagnostics == nil || *decisionErr.Diagnostics != want {
+		t.Errorf("Diagnostics = %+v, want %+v", decisionErr.Diagnostics, want)
+	}
+}
+
+func TestDecideDiagnosticsDisabled(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(compileFailure))
+
+	_, err := engine.Decide(eventRequest())
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) {
+		t.Fatalf("Decide() error = %v, want a DecisionError", err)
+	}
+	if decisionErr.Diagnostics != nil {
+		t.Errorf("Diagnostics = %+v without WithDiagnostics", decisionErr.Diagnostics)
+	}
+}
+
+func TestDecideDiagnosticsWithoutNativeDetail(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(`{"error":"boom","success":false}`), WithDiagnostics())
+
+	_, err := engine.Decide(eventRequest())
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) || decisionErr.Diagnostics == nil || decisionErr.Diagnostics.InputHash == "" {
+		t.Fatalf("Decide() error = %#v, want diagnostics with an input hash", err)
+	}
+}
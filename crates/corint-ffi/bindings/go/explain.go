@@ -0,0 +1,115 @@
+package corint
+
+import "encoding/json"
+
+// ExplanationSchemaVersion is the version of the ExplainJSON output schema.
+// It is incremented whenever fields are removed or change meaning.
+const ExplanationSchemaVersion = 1
+
+// StructuredExplanation is the machine-readable explanation produced by ExplainJSON
+type StructuredExplanation struct {
+	SchemaVersion          int                  `json:"schema_version"`
+	Decision               string               `json:"decision"`
+	Score                  int                  `json:"score"`
+	DecidingRule           string               `json:"deciding_rule,omitempty"`
+	TriggeredRules         []string             `json:"triggered_rules"`
+	ContributingConditions []ExplainedCondition `json:"contributing_conditions"`
+	Conclusion             *ExplainedConclusion `json:"conclusion,omitempty"`
+	Actions                []string             `json:"actions"`
+	Explanation            string               `json:"explanation,omitempty"`
+}
+
+// ExplainedCondition is a condition that evaluated to true in a triggered rule
+type ExplainedCondition struct {
+	RuleID     string      `json:"rule_id"`
+	Expression string      `json:"expression"`
+	Operator   string      `json:"operator,omitempty"`
+	LeftValue  interface{} `json:"left_value,omitempty"`
+	RightValue interface{} `json:"right_value,omitempty"`
+}
+
+// ExplainedConclusion is the ruleset conclusion branch that produced the decision
+type ExplainedConclusion struct {
+	RulesetID string `json:"ruleset_id"`
+	Condition string `json:"condition"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// ExplainJSON returns a structured, versioned explanation of the decision for
+// UIs and APIs. Rule and condition details require the request to enable tracing.
+func (r *DecisionResponse) ExplainJSON() ([]byte, error) {
+	return json.Marshal(r.explain())
+}
+
+// explain builds the structured explanation from the result and trace
+func (r *DecisionResponse) explain() StructuredExplanation {
+	explanation := StructuredExplanation{
+		SchemaVersion:          ExplanationSchemaVersion,
+		Decision:               r.Decision,
+		Score:                  r.Result.Score,
+		TriggeredRules:         append([]string{}, r.Result.TriggeredRules...),
+		ContributingConditions: []ExplainedCondition{},
+		Actions:                append([]string{}, r.Result.Actions...),
+		Explanation:            r.Result.Explanation,
+	}
+
+	pipeline := r.Trace.pipeline()
+	if pipeline == nil {
+		if len(r.Result.TriggeredRules) > 0 {
+			explanation.DecidingRule = r.Result.TriggeredRules[0]
+		}
+		return explanation
+	}
+
+	bestScore := 0
+	for _, ruleset := range pipeline.Rulesets {
+		for _, rule := range ruleset.Rules {
+			if !rule.Triggered {
+				continue
+			}
+			score := 0
+			if rule.Score != nil {
+				score = *rule.Score
+			}
+			if explanation.DecidingRule == "" || score > bestScore {
+				explanation.DecidingRule = rule.RuleID
+				bestScore = score
+			}
+			for _, condition := range flattenConditions(rule.Conditions) {
+				if condition.Result {
+					explanation.ContributingConditions = append(explanation.ContributingConditions, ExplainedCondition{
+						RuleID:     rule.RuleID,
+						Expression: condition.Expression,
+						Operator:   condition.Operator,
+						LeftValue:  condition.LeftValue,
+						RightValue: condition.RightValue,
+					})
+				}
+			}
+		}
+
+		if explanation.Conclusion == nil {
+			for _, conclusion := range ruleset.Conclusion {
+				if conclusion.Matched {
+					explanation.Conclusion = &ExplainedConclusion{
+						RulesetID: ruleset.RulesetID,
+						Condition: conclusion.Condition,
+						Reason:    conclusion.Reason,
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return explanation
+}
+
+// flattenConditions returns the leaf comparisons of conditions
+func flattenConditions(conditions []ConditionEval) []ConditionEval {
+	var leaves []ConditionEval
+	for _, condition := range conditions {
+		leaves = append(leaves, condition.Leaves()...)
+	}
+	return leaves
+}
@@ -0,0 +1,92 @@
+package corint
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// maxExplainBodyBytes is the largest request body accepted by the explain handler
+const maxExplainBodyBytes = 4 << 20
+
+// ExplainHandler decides a single request with tracing forced and responds
+// with its structured explanation. It answers 404 Not Found unless the engine
+// was created with WithExplainEndpoint. Beyond that it performs no access
+// control of its own, so serve it only on an internal route such as /explain
+// or behind the application's own authentication. Tracing is costly and
+// explanations expose rule logic and evaluated operands.
+type ExplainHandler struct {
+	engine Engine
+}
+
+// NewExplainHandler returns a handler accepting the same DecisionRequest body
+// as a decide endpoint and writing the ExplainJSON output
+func NewExplainHandler(e Engine) *ExplainHandler {
+	return &ExplainHandler{engine: e}
+}
+
+// explainEndpointEngine is implemented by engines that report whether
+// WithExplainEndpoint enabled the explain handler for them
+type explainEndpointEngine interface {
+	explainEndpointEnabled() bool
+}
+
+// explainEnabled reports whether the handler may serve explanations for its engine
+func (h *ExplainHandler) explainEnabled() bool {
+	e, ok := h.engine.(explainEndpointEngine)
+	return ok && e.explainEndpointEnabled()
+}
+
+// ServeHTTP implements http.Handler
+func (h *ExplainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.explainEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request DecisionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExplainBodyBytes)).Decode(&request); err != nil {
+		http.Error(w, "malformed request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Options.EnableTrace = true
+
+	response, err := decideContext(r.Context(), h.engine, &request)
+	if err != nil {
+		http.Error(w, err.Error(), decisionErrorStatus(err))
+		return
+	}
+
+	explanation, err := response.ExplainJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(explanation)
+}
+
+// decisionErrorStatus maps a decision error to an HTTP status: invalid
+// requests are the client's fault, unauthorized requests are forbidden and
+// everything else is a server error
+func decisionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrEmptyEventData), errors.Is(err, ErrInvalidOptions), errors.Is(err, ErrInvalidNumber):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// explainEndpointEnabled implements explainEndpointEngine
+func (e *DecisionEngine) explainEndpointEnabled() bool {
+	return e.config.explainEndpoint
+}
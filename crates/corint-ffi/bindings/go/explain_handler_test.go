@@ -0,0 +1,122 @@
+package corint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// explainable enables the explain handler for an engine without engine options
+type explainable struct {
+	Engine
+}
+
+func (explainable) explainEndpointEnabled() bool { return true }
+
+// postExplain serves body to an explain handler over engine and returns the recorded response
+func postExplain(engine Engine, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	NewExplainHandler(engine).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/explain", strings.NewReader(body)))
+	return recorder
+}
+
+func TestExplainHandler(t *testing.T) {
+	var traced bool
+	native := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		traced = strings.Contains(string(requestJSON), `"enable_trace":true`)
+		return []byte(fixtureResponseJSON), nil
+	}, WithExplainEndpoint())
+
+	recorder := postExplain(native, `{"event_data":{"type":"login"}}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", recorder.Code, recorder.Body)
+	}
+	if !traced {
+		t.Error("explain request did not force tracing")
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var explanation StructuredExplanation
+	if err := json.Unmarshal(recorder.Body.Bytes(), &explanation); err != nil {
+		t.Fatalf("decode explanation: %v", err)
+	}
+	if explanation.Decision != "decline" || explanation.DecidingRule != "high_amount" {
+		t.Errorf("explanation = %+v, want decline decided by high_amount", explanation)
+	}
+}
+
+func TestExplainHandlerErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"empty event", ErrEmptyEventData, http.StatusBadRequest},
+		{"invalid options", fmt.Errorf("%w: max_trace_bytes requires enable_trace", ErrInvalidOptions), http.StatusBadRequest},
+		{"invalid number", fmt.Errorf("%w: event_data.amount is NaN", ErrInvalidNumber), http.StatusBadRequest},
+		{"unauthorized", fmt.Errorf("%w: tenant blocked", ErrUnauthorized), http.StatusForbidden},
+		{"native failure", errors.New("native runtime busy"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := explainable{EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+				return nil, tt.err
+			})}
+			if recorder := postExplain(engine, `{"event_data":{}}`); recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainHandlerBadRequests(t *testing.T) {
+	if recorder := postExplain(explainable{answering("approve")}, `{"event_data":`); recorder.Code != http.StatusBadRequest {
+		t.Errorf("malformed body status = %d, want 400", recorder.Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	NewExplainHandler(explainable{answering("approve")}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/explain", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", recorder.Code)
+	}
+}
+
+func TestExplainHandlerAuthorizesWithRequestContext(t *testing.T) {
+	handler := NewExplainHandler(newFakeEngine(t, respondWith(fixtureResponseJSON), WithAuthorizer(tenantAuthorizer), WithExplainEndpoint()))
+
+	for tenant, want := range map[string]int{"acme": http.StatusOK, "blocked": http.StatusForbidden} {
+		request := httptest.NewRequest(http.MethodPost, "/explain", strings.NewReader(`{"event_data":{"type":"login"}}`))
+		request = request.WithContext(context.WithValue(request.Context(), tenantKey{}, tenant))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != want {
+			t.Errorf("tenant %s: status = %d, want %d: %s", tenant, recorder.Code, want, recorder.Body)
+		}
+	}
+}
+
+func TestExplainHandlerDisabled(t *testing.T) {
+	called := false
+	native := newFakeEngine(t, func([]byte) ([]byte, error) {
+		called = true
+		return []byte(fixtureResponseJSON), nil
+	})
+
+	if recorder := postExplain(native, `{"event_data":{"type":"login"}}`); recorder.Code != http.StatusNotFound {
+		t.Errorf("status = %d without WithExplainEndpoint, want 404", recorder.Code)
+	}
+	if recorder := postExplain(answering("decline"), `{"event_data":{"type":"login"}}`); recorder.Code != http.StatusNotFound {
+		t.Errorf("status = %d for an engine without the endpoint option, want 404", recorder.Code)
+	}
+	if called {
+		t.Error("disabled explain handler called the native engine")
+	}
+}
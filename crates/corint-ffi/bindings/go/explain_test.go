@@ -0,0 +1,141 @@
+package corint
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fixtureResponseJSON is a traced native response: two of three rules
+// triggered, for a total score of 100 that declines
+const fixtureResponseJSON = `{
+  "request_id": "req_fixture",
+  "pipeline_id": "login_pipeline",
+  "result": {
+    "signal": {"type": "decline"},
+    "actions": ["BLOCK"],
+    "score": 100,
+    "triggered_rules": ["new_device", "high_amount"],
+    "explanation": "high risk",
+    "context": {"country": "DE"}
+  },
+  "processing_time_ms": 3,
+  "trace": {
+    "pipeline": {
+      "pipeline_id": "login_pipeline",
+      "rulesets": [{
+        "ruleset_id": "login_risk",
+        "rules": [
+          {
+            "rule_id": "new_device", "rule_name": "New Device", "triggered": true, "score": 40,
+            "conditions": [
+              {"expression": "event.device.is_new == true", "left_value": true, "operator": "==", "right_value": true, "result": true}
+            ]
+          },
+          {
+            "rule_id": "high_amount", "rule_name": "High Amount", "triggered": true, "score": 60,
+            "conditions": [{
+              "group_type": "all", "result": true,
+              "nested": [
+                {"expression": "event.amount > 500", "left_value": 1200, "operator": ">", "right_value": 500, "result": true},
+                {"expression": "event.country != \"US\"", "left_value": "DE", "operator": "!=", "right_value": "US", "result": true}
+              ]
+            }]
+          },
+          {
+            "rule_id": "velocity", "rule_name": "Velocity", "triggered": false, "score": 30,
+            "conditions": [
+              {"expression": "features.txn_count_24h >= 10", "left_value": 3, "operator": ">=", "right_value": 10, "result": false}
+            ]
+          }
+        ],
+        "conclusion": [
+          {"condition": "total_score >= 100", "matched": true, "signal": "decline", "reason": "high risk", "total_score": 100},
+          {"condition": "total_score >= 50", "matched": false, "signal": "review"},
+          {"condition": "default", "matched": false, "signal": "approve"}
+        ]
+      }],
+      "final_conclusion": []
+    }
+  }
+}`
+
+// fixtureResponse decodes fixtureResponseJSON as Decide would
+func fixtureResponse(t *testing.T) *DecisionResponse {
+	t.Helper()
+	engine := newFakeEngine(t, respondWith(fixtureResponseJSON))
+	request := eventRequest()
+	request.Options.EnableTrace = true
+	response, err := engine.Decide(request)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	return response
+}
+
+func TestExplainJSON(t *testing.T) {
+	data, err := fixtureResponse(t).ExplainJSON()
+	if err != nil {
+		t.Fatalf("ExplainJSON: %v", err)
+	}
+
+	var explanation StructuredExplanation
+	if err := json.Unmarshal(data, &explanation); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if explanation.SchemaVersion != ExplanationSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", explanation.SchemaVersion, ExplanationSchemaVersion)
+	}
+	if explanation.Decision != "decline" || explanation.Score != 100 {
+		t.Errorf("Decision/Score = %q/%d, want decline/100", explanation.Decision, explanation.Score)
+	}
+	if explanation.DecidingRule != "high_amount" {
+		t.Errorf("DecidingRule = %q, want high_amount", explanation.DecidingRule)
+	}
+	if !reflect.DeepEqual(explanation.Actions, []string{"BLOCK"}) {
+		t.Errorf("Actions = %v, want [BLOCK]", explanation.Actions)
+	}
+
+	var expressions []string
+	for _, condition := range explanation.ContributingConditions {
+		expressions = append(expressions, condition.RuleID+": "+condition.Expression)
+	}
+	wantExpressions := []string{
+		"new_device: event.device.is_new == true",
+		"high_amount: event.amount > 500",
+		`high_amount: event.country != "US"`,
+	}
+	if !reflect.DeepEqual(expressions, wantExpressions) {
+		t.Errorf("contributing conditions = %q, want %q", expressions, wantExpressions)
+	}
+	amount := explanation.ContributingConditions[1]
+	if amount.Operator != ">" || amount.LeftValue != float64(1200) || amount.RightValue != float64(500) {
+		t.Errorf("amount condition = %+v, want 1200 > 500", amount)
+	}
+
+	wantConclusion := &ExplainedConclusion{RulesetID: "login_risk", Condition: "total_score >= 100", Reason: "high risk"}
+	if !reflect.DeepEqual(explanation.Conclusion, wantConclusion) {
+		t.Errorf("Conclusion = %+v, want %+v", explanation.Conclusion, wantConclusion)
+	}
+}
+
+func TestExplainJSONWithoutTrace(t *testing.T) {
+	response := decided("review", "OTP")
+	response.Result.TriggeredRules = []string{"new_device"}
+
+	data, err := response.ExplainJSON()
+	if err != nil {
+		t.Fatalf("ExplainJSON: %v", err)
+	}
+	var explanation StructuredExplanation
+	if err := json.Unmarshal(data, &explanation); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if explanation.DecidingRule != "new_device" {
+		t.Errorf("DecidingRule = %q, want the first triggered rule", explanation.DecidingRule)
+	}
+	if explanation.ContributingConditions == nil || len(explanation.ContributingConditions) != 0 {
+		t.Errorf("ContributingConditions = %v, want an empty list", explanation.ContributingConditions)
+	}
+}
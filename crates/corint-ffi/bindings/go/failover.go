@@ -0,0 +1,111 @@
+package corint
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoHealthyEngine is returned when every engine behind a FailoverEngine is unhealthy
+var ErrNoHealthyEngine = errors.New("no healthy decision engine available")
+
+// HealthChecker is implemented by engines that can report their own health.
+// Engines that do not implement it are always considered healthy.
+type HealthChecker interface {
+	HealthCheck() error
+}
+
+// HealthCheck reports whether the engine can serve decisions. It only checks
+// that the engine has not been closed; it does not probe the native engine,
+// so a native state that fails every decision still reports healthy.
+func (e *DecisionEngine) HealthCheck() error {
+	if e.handle == nil {
+		return ErrEngineClosed
+	}
+	return nil
+}
+
+// FailoverEngine routes decisions to the first healthy engine, in the order given
+type FailoverEngine struct {
+	engines []Engine
+
+	mu      sync.RWMutex
+	healthy []bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// HealthyEngine wraps engines behind health-based failover. Engines are
+// checked immediately and then every checkInterval in the background until
+// Close is called. A non-positive checkInterval disables background checks.
+func HealthyEngine(engines []Engine, checkInterval time.Duration) *FailoverEngine {
+	f := &FailoverEngine{
+		engines: append([]Engine(nil), engines...),
+		healthy: make([]bool, len(engines)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	f.checkAll()
+
+	if checkInterval <= 0 {
+		close(f.done)
+		return f
+	}
+
+	go func() {
+		defer close(f.done)
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				f.checkAll()
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+
+	return f
+}
+
+// checkAll refreshes the health state of every engine
+func (f *FailoverEngine) checkAll() {
+	healthy := make([]bool, len(f.engines))
+	for i, engine := range f.engines {
+		checker, ok := engine.(HealthChecker)
+		healthy[i] = !ok || checker.HealthCheck() == nil
+	}
+
+	f.mu.Lock()
+	f.healthy = healthy
+	f.mu.Unlock()
+}
+
+// Decide executes the decision on the first engine that passed its last health check
+func (f *FailoverEngine) Decide(request *DecisionRequest) (*DecisionResponse, error) {
+	f.mu.RLock()
+	target := -1
+	for i, ok := range f.healthy {
+		if ok {
+			target = i
+			break
+		}
+	}
+	f.mu.RUnlock()
+
+	if target < 0 {
+		return nil, ErrNoHealthyEngine
+	}
+	return f.engines[target].Decide(request)
+}
+
+// Close stops the background health checker. The wrapped engines are not closed.
+func (f *FailoverEngine) Close() {
+	f.stopOnce.Do(func() {
+		close(f.stop)
+	})
+	<-f.done
+}
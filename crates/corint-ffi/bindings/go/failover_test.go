@@ -0,0 +1,87 @@
+package corint
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailoverRoutesAroundUnhealthyEngine(t *testing.T) {
+	primary := answering("approve")
+	secondary := answering("review")
+	failover := HealthyEngine([]Engine{primary, secondary}, 0)
+	defer failover.Close()
+
+	response, err := failover.Decide(eventRequest())
+	if err != nil || response.Decision != "approve" {
+		t.Fatalf("Decide() = %v, %v; want the primary's approve", response, err)
+	}
+
+	primary.healthErr = errors.New("primary down")
+	failover.checkAll()
+	response, err = failover.Decide(eventRequest())
+	if err != nil || response.Decision != "review" {
+		t.Fatalf("Decide() = %v, %v; want the secondary's review", response, err)
+	}
+
+	primary.healthErr = nil
+	failover.checkAll()
+	response, err = failover.Decide(eventRequest())
+	if err != nil || response.Decision != "approve" {
+		t.Fatalf("Decide() = %v, %v; want the recovered primary's approve", response, err)
+	}
+}
+
+func TestFailoverNoHealthyEngine(t *testing.T) {
+	down := answering("approve")
+	down.healthErr = errors.New("down")
+	failover := HealthyEngine([]Engine{down}, 0)
+	defer failover.Close()
+
+	if _, err := failover.Decide(eventRequest()); !errors.Is(err, ErrNoHealthyEngine) {
+		t.Fatalf("Decide() error = %v, want ErrNoHealthyEngine", err)
+	}
+}
+
+// probedEngine is a concurrency-safe health-checked engine counting its health checks
+type probedEngine struct {
+	*stubEngine
+	down   atomic.Bool
+	checks atomic.Int32
+}
+
+func (p *probedEngine) HealthCheck() error {
+	p.checks.Add(1)
+	if p.down.Load() {
+		return errors.New("probe failed")
+	}
+	return nil
+}
+
+func TestFailoverBackgroundChecks(t *testing.T) {
+	primary := &probedEngine{stubEngine: answering("approve")}
+	secondary := answering("review")
+	failover := HealthyEngine([]Engine{primary, secondary}, 5*time.Millisecond)
+
+	primary.down.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for {
+		response, err := failover.Decide(eventRequest())
+		if err == nil && response.Decision == "review" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Decide() = %v, %v; background checks never failed over", response, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	failover.Close()
+	checks := primary.checks.Load()
+	time.Sleep(30 * time.Millisecond)
+	if got := primary.checks.Load(); got != checks {
+		t.Errorf("health checks ran %d more times after Close", got-checks)
+	}
+	failover.Close()
+}
@@ -0,0 +1,84 @@
+package corint
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// FeatureStore fetches precomputed features for an entity. Fetch may return
+// the features it could load together with an error for the rest.
+type FeatureStore interface {
+	Fetch(ctx context.Context, entityID string, features []string) (map[string]interface{}, error)
+}
+
+// FeatureFetchPolicy decides how a failed or partial feature fetch is handled
+type FeatureFetchPolicy int
+
+const (
+	// FailOnFetchError fails the decision when the feature store returns an error
+	FailOnFetchError FeatureFetchPolicy = iota
+	// IgnoreFetchErrors decides with whatever features the store returned
+	IgnoreFetchErrors
+)
+
+// featureEnrichment is the feature store configuration of an engine
+type featureEnrichment struct {
+	store       FeatureStore
+	entityField string
+	features    []string
+}
+
+// WithFeatureStore fetches features from fs before each decision for the
+// entity whose ID is the EventData value at entityField; numeric IDs are
+// passed in plain decimal notation, so 1234567 is fetched as "1234567".
+// Fetched features are merged under the request's own Features, which win on
+// conflict. Requests without the entity field are decided unchanged.
+func WithFeatureStore(fs FeatureStore, entityField string, features ...string) EngineOption {
+	return func(c *engineConfig) {
+		c.featureStore = &featureEnrichment{
+			store:       fs,
+			entityField: entityField,
+			features:    features,
+		}
+	}
+}
+
+// WithFeatureFetchPolicy sets how WithFeatureStore handles fetch errors; the default is FailOnFetchError
+func WithFeatureFetchPolicy(policy FeatureFetchPolicy) EngineOption {
+	return func(c *engineConfig) {
+		c.featureFetchPolicy = policy
+	}
+}
+
+// formatEntityID renders an EventData value as a feature store entity ID.
+// Numbers decoded from JSON are float64, which fmt prints in exponent form
+// from seven digits on.
+func formatEntityID(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// enrich returns a copy of request with fetched features merged in, or request
+// itself when it does not identify an entity
+func (f *featureEnrichment) enrich(ctx context.Context, request *DecisionRequest, policy FeatureFetchPolicy) (*DecisionRequest, error) {
+	value, ok := request.EventData[f.entityField]
+	if !ok || value == nil {
+		return request, nil
+	}
+	entityID := formatEntityID(value)
+
+	fetched, err := f.store.Fetch(ctx, entityID, f.features)
+	if err != nil && policy == FailOnFetchError {
+		return nil, fmt.Errorf("fetch features for %s %q: %w", f.entityField, entityID, err)
+	}
+	if len(fetched) == 0 {
+		return request, nil
+	}
+
+	enriched := *request
+	enriched.Features = MergeFeatures(fetched, request.Features)
+	return &enriched, nil
+}
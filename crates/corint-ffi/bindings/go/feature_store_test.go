@@ -0,0 +1,122 @@
+package corint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeFeatureStore returns fixed features and error, recording the entities fetched
+type fakeFeatureStore struct {
+	features map[string]interface{}
+	err      error
+	fetched  []string
+}
+
+func (s *fakeFeatureStore) Fetch(_ context.Context, entityID string, _ []string) (map[string]interface{}, error) {
+	s.fetched = append(s.fetched, entityID)
+	return s.features, s.err
+}
+
+// sentFeatures returns a native fake that records the features of each request it decides
+func sentFeatures(t *testing.T, sent *map[string]interface{}) func([]byte) ([]byte, error) {
+	return func(requestJSON []byte) ([]byte, error) {
+		var request struct {
+			Features map[string]interface{} `json:"features"`
+		}
+		if err := json.Unmarshal(requestJSON, &request); err != nil {
+			t.Errorf("decode native request: %v", err)
+		}
+		*sent = request.Features
+		return []byte(approveResponse), nil
+	}
+}
+
+func TestFeatureStoreEnrichment(t *testing.T) {
+	store := &fakeFeatureStore{features: map[string]interface{}{"txn_count_24h": 12.0, "risk_tier": "high"}}
+	var sent map[string]interface{}
+	engine := newFakeEngine(t, sentFeatures(t, &sent), WithFeatureStore(store, "user_id", "txn_count_24h", "risk_tier"))
+
+	request := &DecisionRequest{
+		EventData: map[string]interface{}{"user_id": "u1"},
+		Features:  map[string]interface{}{"risk_tier": "low"},
+	}
+	if _, err := engine.Decide(request); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if !reflect.DeepEqual(store.fetched, []string{"u1"}) {
+		t.Errorf("fetched entities = %v, want [u1]", store.fetched)
+	}
+	want := map[string]interface{}{"txn_count_24h": 12.0, "risk_tier": "low"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("native features = %v, want %v with the request's own risk_tier", sent, want)
+	}
+	if !reflect.DeepEqual(request.Features, map[string]interface{}{"risk_tier": "low"}) {
+		t.Errorf("caller's request features changed to %v", request.Features)
+	}
+}
+
+func TestFeatureStoreWithoutEntity(t *testing.T) {
+	store := &fakeFeatureStore{features: map[string]interface{}{"txn_count_24h": 12.0}}
+	var sent map[string]interface{}
+	engine := newFakeEngine(t, sentFeatures(t, &sent), WithFeatureStore(store, "user_id"))
+
+	if _, err := engine.Decide(eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if len(store.fetched) != 0 || len(sent) != 0 {
+		t.Errorf("fetched %v and sent %v for a request without user_id", store.fetched, sent)
+	}
+}
+
+func TestFeatureStorePartialFailure(t *testing.T) {
+	errUnavailable := errors.New("feature shard unavailable")
+	request := &DecisionRequest{EventData: map[string]interface{}{"user_id": "u1"}}
+
+	t.Run("fail", func(t *testing.T) {
+		store := &fakeFeatureStore{features: map[string]interface{}{"txn_count_24h": 12.0}, err: errUnavailable}
+		var calls int
+		engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+			calls++
+			return []byte(approveResponse), nil
+		}, WithFeatureStore(store, "user_id"))
+
+		if _, err := engine.Decide(request); !errors.Is(err, errUnavailable) {
+			t.Fatalf("Decide() error = %v, want the fetch error", err)
+		}
+		if calls != 0 {
+			t.Errorf("native engine called %d times after a failed fetch", calls)
+		}
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		store := &fakeFeatureStore{features: map[string]interface{}{"txn_count_24h": 12.0}, err: errUnavailable}
+		var sent map[string]interface{}
+		engine := newFakeEngine(t, sentFeatures(t, &sent), WithFeatureStore(store, "user_id"), WithFeatureFetchPolicy(IgnoreFetchErrors))
+
+		if _, err := engine.Decide(request); err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+		if !reflect.DeepEqual(sent, map[string]interface{}{"txn_count_24h": 12.0}) {
+			t.Errorf("native features = %v, want the partially fetched features", sent)
+		}
+	})
+}
+
+func TestFeatureStoreNumericEntityID(t *testing.T) {
+	store := &fakeFeatureStore{features: map[string]interface{}{"txn_count_24h": 12.0}}
+	var sent map[string]interface{}
+	engine := newFakeEngine(t, sentFeatures(t, &sent), WithFeatureStore(store, "user_id"))
+
+	for _, id := range []interface{}{1234567.0, 98765432101.0, 12.5} {
+		if _, err := engine.Decide(&DecisionRequest{EventData: map[string]interface{}{"user_id": id}}); err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+	}
+	if want := []string{"1234567", "98765432101", "12.5"}; !reflect.DeepEqual(store.fetched, want) {
+		t.Errorf("fetched entities = %q, want %q", store.fetched, want)
+	}
+}
@@ -0,0 +1,94 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrFeatureConflict is returned by ErrorOnConflict merges when sources disagree on a feature
+var ErrFeatureConflict = errors.New("conflicting feature values")
+
+// ConflictPolicy decides what happens when merged feature sources set the same key
+type ConflictPolicy int
+
+const (
+	// LastWins keeps the value from the later source
+	LastWins ConflictPolicy = iota
+	// ErrorOnConflict fails the merge with ErrFeatureConflict when values differ
+	ErrorOnConflict
+)
+
+// MergeFeatures deep-merges feature maps, with later sources overriding earlier ones
+func MergeFeatures(sources ...map[string]interface{}) map[string]interface{} {
+	merged, _ := MergeFeaturesWithPolicy(LastWins, sources...)
+	return merged
+}
+
+// MergeFeaturesWithPolicy deep-merges feature maps into a new map. Nested maps
+// are merged key by key; any other value present in several sources is
+// resolved by policy. Equal values never conflict. Sources are not modified.
+func MergeFeaturesWithPolicy(policy ConflictPolicy, sources ...map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+	for _, source := range sources {
+		if err := mergeInto(merged, source, "", policy); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// SetMergedFeatures replaces the request's features with the merge of sources
+func (r *DecisionRequest) SetMergedFeatures(policy ConflictPolicy, sources ...map[string]interface{}) error {
+	merged, err := MergeFeaturesWithPolicy(policy, sources...)
+	if err != nil {
+		return err
+	}
+	r.Features = merged
+	return nil
+}
+
+// mergeInto merges source into dst, which is owned by the merge.
+// prefix is the dotted path of dst, used in conflict errors.
+func mergeInto(dst, source map[string]interface{}, prefix string, policy ConflictPolicy) error {
+	for key, value := range source {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = copyFeatureValue(value)
+			continue
+		}
+
+		existingMap, existingIsMap := existing.(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if existingIsMap && valueIsMap {
+			if err := mergeInto(existingMap, valueMap, path, policy); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if policy == ErrorOnConflict && !reflect.DeepEqual(existing, value) {
+			return fmt.Errorf("%w: %s", ErrFeatureConflict, path)
+		}
+		dst[key] = copyFeatureValue(value)
+	}
+	return nil
+}
+
+// copyFeatureValue copies nested maps so later merges never write into a source
+func copyFeatureValue(value interface{}) interface{} {
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	copied := make(map[string]interface{}, len(nested))
+	for k, v := range nested {
+		copied[k] = copyFeatureValue(v)
+	}
+	return copied
+}
@@ -0,0 +1,81 @@
+package corint
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeFeaturesNested(t *testing.T) {
+	store := map[string]interface{}{
+		"user":     map[string]interface{}{"txn_count_24h": 3.0, "profile": map[string]interface{}{"age_days": 400.0}},
+		"device":   "known",
+		"velocity": 1.0,
+	}
+	realtime := map[string]interface{}{
+		"user":     map[string]interface{}{"txn_count_24h": 5.0, "profile": map[string]interface{}{"country": "DE"}},
+		"velocity": 2.0,
+	}
+
+	merged := MergeFeatures(store, realtime)
+	want := map[string]interface{}{
+		"user":     map[string]interface{}{"txn_count_24h": 5.0, "profile": map[string]interface{}{"age_days": 400.0, "country": "DE"}},
+		"device":   "known",
+		"velocity": 2.0,
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("MergeFeatures() = %v, want %v", merged, want)
+	}
+
+	merged["user"].(map[string]interface{})["profile"].(map[string]interface{})["age_days"] = 0.0
+	if store["user"].(map[string]interface{})["profile"].(map[string]interface{})["age_days"] != 400.0 {
+		t.Error("writing to the merge modified a source")
+	}
+}
+
+func TestMergeFeaturesErrorOnConflict(t *testing.T) {
+	a := map[string]interface{}{"user": map[string]interface{}{"tier": "gold", "score": 7.0}}
+
+	agreeing := map[string]interface{}{"user": map[string]interface{}{"tier": "gold"}, "extra": true}
+	merged, err := MergeFeaturesWithPolicy(ErrorOnConflict, a, agreeing)
+	if err != nil {
+		t.Fatalf("MergeFeaturesWithPolicy with equal values: %v", err)
+	}
+	if merged["extra"] != true || merged["user"].(map[string]interface{})["score"] != 7.0 {
+		t.Errorf("merged = %v", merged)
+	}
+
+	conflicting := map[string]interface{}{"user": map[string]interface{}{"tier": "silver"}}
+	_, err = MergeFeaturesWithPolicy(ErrorOnConflict, a, conflicting)
+	if !errors.Is(err, ErrFeatureConflict) {
+		t.Fatalf("MergeFeaturesWithPolicy() error = %v, want ErrFeatureConflict", err)
+	}
+	if !strings.Contains(err.Error(), "user.tier") {
+		t.Errorf("error %q does not name user.tier", err)
+	}
+
+	if _, err := MergeFeaturesWithPolicy(LastWins, a, conflicting); err != nil {
+		t.Errorf("LastWins merge failed: %v", err)
+	}
+}
+
+func TestSetMergedFeatures(t *testing.T) {
+	request := eventRequest()
+	request.Features = map[string]interface{}{"stale": true}
+
+	err := request.SetMergedFeatures(ErrorOnConflict, map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 2.0})
+	if !errors.Is(err, ErrFeatureConflict) {
+		t.Fatalf("SetMergedFeatures() error = %v, want ErrFeatureConflict", err)
+	}
+	if request.Features["stale"] != true {
+		t.Error("failed merge replaced the request's features")
+	}
+
+	if err := request.SetMergedFeatures(LastWins, map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 2.0}); err != nil {
+		t.Fatalf("SetMergedFeatures: %v", err)
+	}
+	if !reflect.DeepEqual(request.Features, map[string]interface{}{"a": 2.0}) {
+		t.Errorf("Features = %v", request.Features)
+	}
+}
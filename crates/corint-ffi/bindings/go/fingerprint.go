@@ -0,0 +1,83 @@
+package corint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// FingerprintIgnoredMetadata lists metadata keys left out of Fingerprint.
+// Native metadata echoes the request's, so these are the per-request
+// identifiers DefaultHeaderMapping copies in from HTTP headers.
+var FingerprintIgnoredMetadata = map[string]bool{
+	"request_id":     true,
+	"correlation_id": true,
+}
+
+// fingerprintedResult is the part of a DecisionResult hashed by Fingerprint
+type fingerprintedResult struct {
+	Signal         string                     `json:"signal"`
+	Score          int                        `json:"score"`
+	Actions        []string                   `json:"actions"`
+	TriggeredRules []string                   `json:"triggered_rules"`
+	Outputs        map[string]json.RawMessage `json:"outputs"`
+}
+
+// newFingerprintedResult returns the hashed fields of result with signal as
+// its decision and actions and triggered rules sorted
+func newFingerprintedResult(signal string, result DecisionResult) fingerprintedResult {
+	actions := append([]string(nil), result.Actions...)
+	sort.Strings(actions)
+	triggered := append([]string(nil), result.TriggeredRules...)
+	sort.Strings(triggered)
+	return fingerprintedResult{
+		Signal:         signal,
+		Score:          result.Score,
+		Actions:        actions,
+		TriggeredRules: triggered,
+		Outputs:        result.Outputs,
+	}
+}
+
+// Fingerprint returns a stable hex SHA-256 over the outcome of a decision:
+// the decision, score, sorted actions and triggered rules and outputs of the
+// result and of each sub-decision, and metadata except the keys in
+// FingerprintIgnoredMetadata. Everything else, including the request ID,
+// timing, explanation, context and trace, is excluded: the native engine
+// generates a new request ID for every evaluation, so evaluations with the
+// same logical outcome share a fingerprint.
+func (r *DecisionResponse) Fingerprint() (string, error) {
+	decisions := make(map[string]fingerprintedResult, len(r.Decisions))
+	for name, result := range r.Decisions {
+		var signal string
+		if result.Signal != nil {
+			signal = result.Signal.Type
+		}
+		decisions[name] = newFingerprintedResult(signal, result)
+	}
+
+	metadata := make(map[string]string, len(r.Metadata))
+	for key, value := range r.Metadata {
+		if !FingerprintIgnoredMetadata[key] {
+			metadata[key] = value
+		}
+	}
+
+	// Field order is fixed by the structs and map keys are sorted by encoding/json
+	data, err := json.Marshal(struct {
+		Result    fingerprintedResult            `json:"result"`
+		Decisions map[string]fingerprintedResult `json:"decisions"`
+		Metadata  map[string]string              `json:"metadata"`
+	}{
+		Result:    newFingerprintedResult(r.Decision, r.Result),
+		Decisions: decisions,
+		Metadata:  metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
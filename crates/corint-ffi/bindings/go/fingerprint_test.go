@@ -0,0 +1,70 @@
+package corint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// fingerprinted returns a decline response with the given request ID, latency and actions
+func fingerprinted(requestID string, latencyMs uint64, actions ...string) *DecisionResponse {
+	response := decided("decline", actions...)
+	response.RequestID = requestID
+	response.ProcessingTimeMs = latencyMs
+	response.Result.Score = 90
+	response.Result.TriggeredRules = []string{"velocity", "new_device"}
+	response.Result.Outputs = map[string]json.RawMessage{"report": json.RawMessage(`{"reason":"velocity"}`)}
+	response.Decisions = map[string]DecisionResult{
+		"compliance": {Signal: &DecisionSignal{Type: "review"}, Actions: []string{"KYC", "HOLD"}},
+	}
+	response.Metadata = map[string]string{"tenant_id": "acme", "request_id": requestID, "correlation_id": "corr_" + requestID}
+	return response
+}
+
+// mustFingerprint returns the fingerprint of r, failing the test on error
+func mustFingerprint(t *testing.T, r *DecisionResponse) string {
+	t.Helper()
+	fingerprint, err := r.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	return fingerprint
+}
+
+func TestFingerprintIgnoresDeliveryDetails(t *testing.T) {
+	first := fingerprinted("req_1_a", 3, "BLOCK", "NOTIFY")
+	redelivered := fingerprinted("req_2_b", 41, "NOTIFY", "BLOCK")
+	redelivered.Result.TriggeredRules = []string{"new_device", "velocity"}
+	redelivered.Result.Explanation = "redelivered"
+	redelivered.Decisions["compliance"] = DecisionResult{Signal: &DecisionSignal{Type: "review"}, Actions: []string{"HOLD", "KYC"}}
+	redelivered.Trace = Trace{"pipeline": map[string]interface{}{"pipeline_id": "login_pipeline"}}
+
+	if a, b := mustFingerprint(t, first), mustFingerprint(t, redelivered); a != b {
+		t.Errorf("fingerprints differ for the same outcome: %s != %s", a, b)
+	}
+}
+
+func TestFingerprintDetectsDifferentOutcomes(t *testing.T) {
+	base := mustFingerprint(t, fingerprinted("req_1", 3, "BLOCK"))
+
+	changes := map[string]func(*DecisionResponse){
+		"decision": func(r *DecisionResponse) { r.Decision = "review" },
+		"score":    func(r *DecisionResponse) { r.Result.Score = 91 },
+		"actions":  func(r *DecisionResponse) { r.Result.Actions = append(r.Result.Actions, "NOTIFY") },
+		"rules":    func(r *DecisionResponse) { r.Result.TriggeredRules = r.Result.TriggeredRules[:1] },
+		"metadata": func(r *DecisionResponse) { r.Metadata["tenant_id"] = "globex" },
+		"outputs":  func(r *DecisionResponse) { r.Result.Outputs["report"] = json.RawMessage(`{"reason":"amount"}`) },
+		"sub-decision": func(r *DecisionResponse) {
+			r.Decisions["compliance"] = DecisionResult{Signal: &DecisionSignal{Type: "decline"}, Actions: []string{"KYC", "HOLD"}}
+		},
+		"sub-decision added": func(r *DecisionResponse) {
+			r.Decisions["aml"] = DecisionResult{Signal: &DecisionSignal{Type: "approve"}}
+		},
+	}
+	for name, change := range changes {
+		response := fingerprinted("req_1", 3, "BLOCK")
+		change(response)
+		if mustFingerprint(t, response) == base {
+			t.Errorf("changing %s kept the fingerprint", name)
+		}
+	}
+}
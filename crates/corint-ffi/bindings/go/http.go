@@ -0,0 +1,73 @@
+package corint
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultHeaderMapping maps HTTP header names to the metadata keys they are copied into
+var DefaultHeaderMapping = map[string]string{
+	"X-Request-Id":     "request_id",
+	"X-Correlation-Id": "correlation_id",
+	"X-Tenant-Id":      "tenant_id",
+}
+
+// HTTPRequestMapper builds decision requests from incoming HTTP requests
+type HTTPRequestMapper struct {
+	// HeaderMapping maps HTTP header names to metadata keys.
+	// Headers that are absent from the request are skipped.
+	HeaderMapping map[string]string
+}
+
+// RequestFromHTTP builds a decision request from an HTTP request using DefaultHeaderMapping
+func RequestFromHTTP(r *http.Request) (*DecisionRequest, error) {
+	mapper := HTTPRequestMapper{HeaderMapping: DefaultHeaderMapping}
+	return mapper.FromRequest(r)
+}
+
+// FromRequest extracts the client IP, user agent, method and path into EventData
+// and copies the mapped headers into Metadata
+func (m HTTPRequestMapper) FromRequest(r *http.Request) (*DecisionRequest, error) {
+	if r == nil {
+		return nil, errors.New("http request is nil")
+	}
+
+	eventData := map[string]interface{}{
+		"ip_address":  clientIP(r),
+		"user_agent":  r.UserAgent(),
+		"http_method": r.Method,
+	}
+	if r.URL != nil {
+		eventData["http_path"] = r.URL.Path
+	}
+
+	metadata := make(map[string]string)
+	for header, key := range m.HeaderMapping {
+		if value := r.Header.Get(header); value != "" {
+			metadata[key] = value
+		}
+	}
+
+	return &DecisionRequest{
+		EventData: eventData,
+		Metadata:  metadata,
+	}, nil
+}
+
+// clientIP returns the originating client address, preferring the first X-Forwarded-For hop
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,101 @@
+package corint
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestFromHTTP(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/login?next=/home", nil)
+	r.RemoteAddr = "10.0.0.7:52341"
+	r.Header.Set("User-Agent", "corint-test/1.0")
+	r.Header.Set("X-Forwarded-For", " 203.0.113.9 , 10.0.0.1")
+	r.Header.Set("X-Request-Id", "req-42")
+	r.Header.Set("X-Tenant-Id", "acme")
+
+	request, err := RequestFromHTTP(r)
+	if err != nil {
+		t.Fatalf("RequestFromHTTP: %v", err)
+	}
+
+	wantEvent := map[string]interface{}{
+		"ip_address":  "203.0.113.9",
+		"user_agent":  "corint-test/1.0",
+		"http_method": "POST",
+		"http_path":   "/v1/login",
+	}
+	for key, want := range wantEvent {
+		if got := request.EventData[key]; got != want {
+			t.Errorf("EventData[%q] = %v, want %v", key, got, want)
+		}
+	}
+
+	wantMetadata := map[string]string{"request_id": "req-42", "tenant_id": "acme"}
+	if len(request.Metadata) != len(wantMetadata) {
+		t.Errorf("Metadata = %v, want %v", request.Metadata, wantMetadata)
+	}
+	for key, want := range wantMetadata {
+		if got := request.Metadata[key]; got != want {
+			t.Errorf("Metadata[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestClientIPFallsBackToRemoteAddr(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"host and port", "192.0.2.10:8080", "", "192.0.2.10"},
+		{"ipv6 host and port", "[2001:db8::1]:443", "", "2001:db8::1"},
+		{"no port", "192.0.2.11", "", "192.0.2.11"},
+		{"empty forwarded hop", "192.0.2.12:1234", " , 198.51.100.1", "192.0.2.12"},
+		{"forwarded wins", "192.0.2.13:1234", "198.51.100.2", "198.51.100.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestMapperCustomHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "/checkout", nil)
+	r.Header.Set("X-Session", "s-1")
+	r.Header.Set("X-Request-Id", "ignored")
+
+	mapper := HTTPRequestMapper{HeaderMapping: map[string]string{
+		"X-Session": "session_id",
+		"X-Missing": "missing",
+	}}
+	request, err := mapper.FromRequest(r)
+	if err != nil {
+		t.Fatalf("FromRequest: %v", err)
+	}
+
+	if got := request.Metadata["session_id"]; got != "s-1" {
+		t.Errorf("Metadata[session_id] = %q, want %q", got, "s-1")
+	}
+	if _, ok := request.Metadata["missing"]; ok {
+		t.Error("absent header was copied into metadata")
+	}
+	if _, ok := request.Metadata["request_id"]; ok {
+		t.Error("unmapped header was copied into metadata")
+	}
+}
+
+func TestFromRequestNil(t *testing.T) {
+	if _, err := RequestFromHTTP(nil); err == nil {
+		t.Fatal("expected an error for a nil request")
+	}
+}
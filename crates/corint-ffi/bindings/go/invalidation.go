@@ -0,0 +1,28 @@
+package corint
+
+import "context"
+
+// CacheInvalidationBus broadcasts cache invalidations between instances. The
+// redisbus module implements it over Redis pub/sub. Implementations deliver a publish to every
+// subscriber, including those of the publishing instance.
+type CacheInvalidationBus interface {
+	// Publish announces that cached decisions are stale
+	Publish(ctx context.Context) error
+	// Subscribe calls fn for every published invalidation until the returned
+	// function is called or ctx is done
+	Subscribe(ctx context.Context, fn func()) (unsubscribe func(), err error)
+}
+
+// InvalidateOn clears the cache whenever an invalidation is published on bus
+func (c *CachingEngine) InvalidateOn(ctx context.Context, bus CacheInvalidationBus) (unsubscribe func(), err error) {
+	return bus.Subscribe(ctx, c.Invalidate)
+}
+
+// ReloadAndPublish reloads e and, if that succeeds, publishes an invalidation
+// so caches subscribed on every instance drop decisions made with the old rules
+func ReloadAndPublish(ctx context.Context, e *DecisionEngine, bus CacheInvalidationBus) error {
+	if err := e.Reload(); err != nil {
+		return err
+	}
+	return bus.Publish(ctx)
+}
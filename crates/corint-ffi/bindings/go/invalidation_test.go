@@ -0,0 +1,121 @@
+package corint
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBus is an in-process CacheInvalidationBus delivering publishes synchronously
+type fakeBus struct {
+	mu          sync.Mutex
+	subscribers map[int]func()
+	next        int
+	published   int
+}
+
+func newFakeBus() *fakeBus {
+	return &fakeBus{subscribers: make(map[int]func())}
+}
+
+func (b *fakeBus) Publish(context.Context) error {
+	b.mu.Lock()
+	b.published++
+	subscribers := make([]func(), 0, len(b.subscribers))
+	for _, fn := range b.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	b.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+	return nil
+}
+
+func (b *fakeBus) Subscribe(_ context.Context, fn func()) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	b.subscribers[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}, nil
+}
+
+// warmCache returns a cache subscribed on bus holding one cached decision
+func warmCache(t *testing.T, bus CacheInvalidationBus) *CachingEngine {
+	t.Helper()
+	engine, _ := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+	unsubscribe, err := cache.InvalidateOn(context.Background(), bus)
+	if err != nil {
+		t.Fatalf("InvalidateOn: %v", err)
+	}
+	t.Cleanup(unsubscribe)
+
+	if _, err := cache.Decide(userRequest("u1", 10)); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf("Len() = %d after warming, want 1", cache.Len())
+	}
+	return cache
+}
+
+func TestReloadAndPublishInvalidatesEveryInstance(t *testing.T) {
+	bus := newFakeBus()
+	local, remote := warmCache(t, bus), warmCache(t, bus)
+	fakeReload(t, 0, nil)
+
+	if err := ReloadAndPublish(context.Background(), newFakeEngine(t, respondWith(approveResponse)), bus); err != nil {
+		t.Fatalf("ReloadAndPublish: %v", err)
+	}
+	if local.Len() != 0 || remote.Len() != 0 {
+		t.Errorf("Len() = %d/%d after publish, want both caches cleared", local.Len(), remote.Len())
+	}
+}
+
+func TestReloadAndPublishSkipsPublishOnFailedReload(t *testing.T) {
+	bus := newFakeBus()
+	cache := warmCache(t, bus)
+	fakeReload(t, -1, nil)
+
+	if err := ReloadAndPublish(context.Background(), newFakeEngine(t, respondWith(approveResponse)), bus); err == nil {
+		t.Fatal("ReloadAndPublish succeeded on a failed reload")
+	}
+	if bus.published != 0 || cache.Len() != 1 {
+		t.Errorf("published %d times, Len() = %d, want no publish and the cache kept", bus.published, cache.Len())
+	}
+}
+
+func TestInvalidateOnUnsubscribe(t *testing.T) {
+	bus := newFakeBus()
+	engine, _ := countingEngine()
+	cache, err := NewCachingEngineWithKey(engine, userKey, time.Minute)
+	if err != nil {
+		t.Fatalf("NewCachingEngineWithKey: %v", err)
+	}
+	unsubscribe, err := cache.InvalidateOn(context.Background(), bus)
+	if err != nil {
+		t.Fatalf("InvalidateOn: %v", err)
+	}
+	unsubscribe()
+
+	if _, err := cache.Decide(userRequest("u1", 10)); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if err := bus.Publish(context.Background()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Len() = %d, want the unsubscribed cache kept", cache.Len())
+	}
+}
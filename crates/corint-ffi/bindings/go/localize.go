@@ -0,0 +1,124 @@
+package corint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MessageCatalog holds localized message templates keyed by language and reason code.
+//
+// Reason codes are "decision.<signal>" (e.g. "decision.decline") for the
+// decision itself and the IDs of triggered rules. Templates reference
+// parameters as {name}; available parameters are decision, score, rule_id,
+// rule_score and every key of the result context.
+type MessageCatalog struct {
+	// DefaultLanguage is used when a reason code has no message in the requested language
+	DefaultLanguage string
+	// Messages maps a language tag to reason codes and their templates
+	Messages map[string]map[string]string
+}
+
+// lookup returns the template for code in lang, falling back to the default language
+func (c MessageCatalog) lookup(lang, code string) (string, bool) {
+	if template, ok := c.Messages[lang][code]; ok {
+		return template, true
+	}
+	template, ok := c.Messages[c.DefaultLanguage][code]
+	return template, ok
+}
+
+// ExplainLocalized renders the decision and each triggered rule using the
+// catalog's templates for lang, one message per line. A triggered rule without
+// a template falls back to its name from the trace, or its ID without a trace;
+// only a missing decision template is an error.
+func (r *DecisionResponse) ExplainLocalized(lang string, messages MessageCatalog) (string, error) {
+	params := map[string]interface{}{
+		"decision": r.Decision,
+		"score":    r.Result.Score,
+	}
+	for key, value := range r.Result.Context {
+		if _, reserved := params[key]; !reserved {
+			params[key] = value
+		}
+	}
+
+	ruleScores := make(map[string]int)
+	ruleNames := make(map[string]string)
+	for _, rule := range r.Trace.RuleEvals() {
+		if rule.Triggered {
+			ruleScores[rule.RuleID] = rule.Score
+			ruleNames[rule.RuleID] = rule.RuleName
+		}
+	}
+
+	var lines []string
+
+	decisionCode := "decision." + r.Decision
+	template, ok := messages.lookup(lang, decisionCode)
+	if !ok {
+		return "", fmt.Errorf("no message for reason code %q", decisionCode)
+	}
+	lines = append(lines, renderTemplate(template, params))
+
+	for _, ruleID := range r.Result.TriggeredRules {
+		template, ok := messages.lookup(lang, ruleID)
+		if !ok {
+			lines = append(lines, defaultRuleExplanation(ruleID, ruleNames[ruleID]))
+			continue
+		}
+		params["rule_id"] = ruleID
+		params["rule_score"] = ruleScores[ruleID]
+		lines = append(lines, renderTemplate(template, params))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// defaultRuleExplanation describes a rule without a localized template
+func defaultRuleExplanation(ruleID, ruleName string) string {
+	if ruleName != "" {
+		return ruleName
+	}
+	return ruleID
+}
+
+// renderTemplate replaces {name} placeholders with params values; unknown placeholders are kept
+func renderTemplate(template string, params map[string]interface{}) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		name := template[start+1 : end]
+		if value, ok := params[name]; ok {
+			b.WriteString(formatParam(value))
+		} else {
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// formatParam formats a template parameter, writing floats such as decoded
+// JSON numbers in plain decimal notation rather than exponent form
+func formatParam(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(value)
+	}
+}
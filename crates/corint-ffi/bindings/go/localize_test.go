@@ -0,0 +1,82 @@
+package corint
+
+import "testing"
+
+// fixtureCatalog has English and German messages for the fixture response,
+// with German missing the high_amount message
+var fixtureCatalog = MessageCatalog{
+	DefaultLanguage: "en",
+	Messages: map[string]map[string]string{
+		"en": {
+			"decision.decline": "Declined with score {score} in {country}",
+			"new_device":       "New device (+{rule_score})",
+			"high_amount":      "High amount (+{rule_score})",
+		},
+		"de": {
+			"decision.decline": "Abgelehnt mit Punktzahl {score} in {country}",
+			"new_device":       "Neues Gerät (+{rule_score}) {unknown}",
+		},
+	},
+}
+
+func TestExplainLocalized(t *testing.T) {
+	response := fixtureResponse(t)
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"en", "Declined with score 100 in DE\nNew device (+40)\nHigh amount (+60)"},
+		{"de", "Abgelehnt mit Punktzahl 100 in DE\nNeues Gerät (+40) {unknown}\nHigh amount (+60)"},
+		{"fr", "Declined with score 100 in DE\nNew device (+40)\nHigh amount (+60)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			got, err := response.ExplainLocalized(tt.lang, fixtureCatalog)
+			if err != nil {
+				t.Fatalf("ExplainLocalized: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExplainLocalized(%q) =\n%s\nwant\n%s", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplainLocalizedMissingRuleMessage(t *testing.T) {
+	catalog := MessageCatalog{DefaultLanguage: "en", Messages: map[string]map[string]string{
+		"en": {"decision.decline": "Declined", "new_device": "New device"},
+	}}
+	got, err := fixtureResponse(t).ExplainLocalized("en", catalog)
+	if err != nil {
+		t.Fatalf("ExplainLocalized: %v", err)
+	}
+	if want := "Declined\nNew device\nHigh Amount"; got != want {
+		t.Errorf("ExplainLocalized() =\n%s\nwant the rule name for high_amount:\n%s", got, want)
+	}
+}
+
+func TestExplainLocalizedMissingDecisionMessage(t *testing.T) {
+	catalog := MessageCatalog{DefaultLanguage: "en", Messages: map[string]map[string]string{
+		"en": {"new_device": "New device", "high_amount": "High amount"},
+	}}
+	if _, err := fixtureResponse(t).ExplainLocalized("en", catalog); err == nil {
+		t.Fatal("expected an error for a decision without a message")
+	}
+}
+
+func TestExplainLocalizedLargeAmounts(t *testing.T) {
+	response := decided("review")
+	response.Result.Context = map[string]interface{}{"amount": 2500000.0, "ratio": 0.25}
+	catalog := MessageCatalog{DefaultLanguage: "en", Messages: map[string]map[string]string{
+		"en": {"decision.review": "Review {amount} at {ratio}"},
+	}}
+
+	got, err := response.ExplainLocalized("en", catalog)
+	if err != nil {
+		t.Fatalf("ExplainLocalized: %v", err)
+	}
+	if want := "Review 2500000 at 0.25"; got != want {
+		t.Errorf("ExplainLocalized() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package corint
+
+/*
+#include <stdlib.h>
+
+char* corint_recent_logs(size_t n);
+void corint_string_free(char* s);
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+)
+
+// nativeRecentLogs returns the JSON array of up to n recent native log lines;
+// tests replace it to fake the native buffer
+var nativeRecentLogs = func(n int) ([]byte, error) {
+	logsPtr := C.corint_recent_logs(C.size_t(n))
+	if logsPtr == nil {
+		return nil, errors.New("failed to read native logs")
+	}
+	defer C.corint_string_free(logsPtr)
+	return []byte(C.GoString(logsPtr)), nil
+}
+
+// RecentLogs returns up to n of the most recent native log lines, oldest first.
+// The native log buffer is shared by all engines in the process; its size is
+// set with RuntimeConfig.LogBufferSize.
+func (e *DecisionEngine) RecentLogs(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	logsJSON, err := nativeRecentLogs(n)
+	if err != nil {
+		return nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(logsJSON, &lines); err != nil {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package corint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// fakeRecentLogs serves RecentLogs from lines for the rest of the test
+func fakeRecentLogs(t *testing.T, lines *[]string) {
+	t.Helper()
+	original := nativeRecentLogs
+	nativeRecentLogs = func(n int) ([]byte, error) {
+		recent := *lines
+		if len(recent) > n {
+			recent = recent[len(recent)-n:]
+		}
+		return json.Marshal(recent)
+	}
+	t.Cleanup(func() { nativeRecentLogs = original })
+}
+
+func TestRecentLogsAfterDecision(t *testing.T) {
+	var lines []string
+	fakeRecentLogs(t, &lines)
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		lines = append(lines, fmt.Sprintf("INFO corint_sdk: decision %d complete", len(lines)+1))
+		return []byte(approveResponse), nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := engine.Decide(eventRequest()); err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+	}
+
+	want := []string{
+		"INFO corint_sdk: decision 2 complete",
+		"INFO corint_sdk: decision 3 complete",
+	}
+	if got := engine.RecentLogs(2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RecentLogs(2) = %q, want %q", got, want)
+	}
+}
+
+func TestRecentLogsInvalid(t *testing.T) {
+	original := nativeRecentLogs
+	t.Cleanup(func() { nativeRecentLogs = original })
+	engine := &DecisionEngine{}
+
+	nativeRecentLogs = func(int) ([]byte, error) { return []byte("not json"), nil }
+	if got := engine.RecentLogs(5); got != nil {
+		t.Errorf("RecentLogs with malformed native output = %q, want nil", got)
+	}
+
+	nativeRecentLogs = func(int) ([]byte, error) {
+		t.Error("native buffer read for a non-positive count")
+		return nil, nil
+	}
+	if got := engine.RecentLogs(0); got != nil {
+		t.Errorf("RecentLogs(0) = %q, want nil", got)
+	}
+}
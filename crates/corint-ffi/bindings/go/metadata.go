@@ -0,0 +1,60 @@
+package corint
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metadata provides typed access to response metadata values.
+// Each getter reports false when the key is missing or the value cannot be parsed.
+type Metadata map[string]string
+
+// Meta returns the response metadata wrapped in typed getters
+func (r *DecisionResponse) Meta() Metadata {
+	return Metadata(r.Metadata)
+}
+
+// GetString returns the raw value for key
+func (m Metadata) GetString(key string) (string, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+// GetInt returns the value for key parsed as a base-10 integer
+func (m Metadata) GetInt(key string) (int64, bool) {
+	value, ok := m[key]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// GetFloat returns the value for key parsed as a floating point number
+func (m Metadata) GetFloat(key string) (float64, bool) {
+	value, ok := m[key]
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// GetTime returns the value for key parsed as an RFC 3339 timestamp
+func (m Metadata) GetTime(key string) (time.Time, bool) {
+	value, ok := m[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,75 @@
+package corint
+
+import (
+	"testing"
+	"time"
+)
+
+func testMetadata() Metadata {
+	response := &DecisionResponse{Metadata: map[string]string{
+		"tenant":     "acme",
+		"attempts":   "3",
+		"risk":       "0.75",
+		"decided_at": "2024-05-01T12:30:00.5Z",
+		"bad_number": "three",
+		"bad_time":   "yesterday",
+	}}
+	return response.Meta()
+}
+
+func TestMetadataGetString(t *testing.T) {
+	m := testMetadata()
+	if got, ok := m.GetString("tenant"); !ok || got != "acme" {
+		t.Errorf("GetString(tenant) = %q, %v", got, ok)
+	}
+	if got, ok := m.GetString("missing"); ok || got != "" {
+		t.Errorf("GetString(missing) = %q, %v; want \"\", false", got, ok)
+	}
+}
+
+func TestMetadataGetInt(t *testing.T) {
+	m := testMetadata()
+	if got, ok := m.GetInt("attempts"); !ok || got != 3 {
+		t.Errorf("GetInt(attempts) = %d, %v", got, ok)
+	}
+	for _, key := range []string{"missing", "bad_number", "risk"} {
+		if got, ok := m.GetInt(key); ok || got != 0 {
+			t.Errorf("GetInt(%s) = %d, %v; want 0, false", key, got, ok)
+		}
+	}
+}
+
+func TestMetadataGetFloat(t *testing.T) {
+	m := testMetadata()
+	if got, ok := m.GetFloat("risk"); !ok || got != 0.75 {
+		t.Errorf("GetFloat(risk) = %v, %v", got, ok)
+	}
+	if got, ok := m.GetFloat("attempts"); !ok || got != 3 {
+		t.Errorf("GetFloat(attempts) = %v, %v", got, ok)
+	}
+	for _, key := range []string{"missing", "bad_number"} {
+		if got, ok := m.GetFloat(key); ok || got != 0 {
+			t.Errorf("GetFloat(%s) = %v, %v; want 0, false", key, got, ok)
+		}
+	}
+}
+
+func TestMetadataGetTime(t *testing.T) {
+	m := testMetadata()
+	want := time.Date(2024, 5, 1, 12, 30, 0, 500_000_000, time.UTC)
+	if got, ok := m.GetTime("decided_at"); !ok || !got.Equal(want) {
+		t.Errorf("GetTime(decided_at) = %v, %v; want %v", got, ok, want)
+	}
+	for _, key := range []string{"missing", "bad_time", "attempts"} {
+		if got, ok := m.GetTime(key); ok || !got.IsZero() {
+			t.Errorf("GetTime(%s) = %v, %v; want zero, false", key, got, ok)
+		}
+	}
+}
+
+func TestMetadataNil(t *testing.T) {
+	m := (&DecisionResponse{}).Meta()
+	if _, ok := m.GetString("tenant"); ok {
+		t.Error("GetString on nil metadata reported a value")
+	}
+}
@@ -0,0 +1,38 @@
+package corint
+
+import "time"
+
+// EngineFunc adapts a function to the Engine interface
+type EngineFunc func(request *DecisionRequest) (*DecisionResponse, error)
+
+// Decide calls f(request)
+func (f EngineFunc) Decide(request *DecisionRequest) (*DecisionResponse, error) {
+	return f(request)
+}
+
+// Middleware wraps an Engine with additional behavior
+type Middleware func(next Engine) Engine
+
+// Wrap applies middleware to e. The first middleware is the outermost.
+func Wrap(e Engine, middleware ...Middleware) Engine {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		e = middleware[i](e)
+	}
+	return e
+}
+
+// WithLatencyBudget measures each decision and calls onExceeded with the
+// request and measured latency when it takes longer than budget. The
+// decision result is returned unchanged.
+func WithLatencyBudget(budget time.Duration, onExceeded func(*DecisionRequest, time.Duration)) Middleware {
+	return func(next Engine) Engine {
+		return EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+			start := time.Now()
+			response, err := next.Decide(request)
+			if elapsed := time.Since(start); elapsed > budget && onExceeded != nil {
+				onExceeded(request, elapsed)
+			}
+			return response, err
+		})
+	}
+}
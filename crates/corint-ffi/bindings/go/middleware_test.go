@@ -0,0 +1,61 @@
+package corint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithLatencyBudget(t *testing.T) {
+	slow := EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		time.Sleep(20 * time.Millisecond)
+		return decided("approve"), nil
+	})
+
+	var (
+		exceeded []time.Duration
+		seen     *DecisionRequest
+	)
+	record := func(request *DecisionRequest, elapsed time.Duration) {
+		seen = request
+		exceeded = append(exceeded, elapsed)
+	}
+
+	request := eventRequest()
+	response, err := Wrap(slow, WithLatencyBudget(5*time.Millisecond, record)).Decide(request)
+	if err != nil || response.Decision != "approve" {
+		t.Fatalf("Decide() = %v, %v; want the wrapped approve", response, err)
+	}
+	if len(exceeded) != 1 || seen != request {
+		t.Fatalf("onExceeded called %d times with %p, want once with the request", len(exceeded), seen)
+	}
+	if exceeded[0] < 20*time.Millisecond {
+		t.Errorf("reported latency %v, want at least 20ms", exceeded[0])
+	}
+
+	exceeded = nil
+	if _, err := Wrap(slow, WithLatencyBudget(time.Second, record)).Decide(request); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if len(exceeded) != 0 {
+		t.Errorf("onExceeded called within budget: %v", exceeded)
+	}
+}
+
+func TestWrapOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next Engine) Engine {
+			return EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+				order = append(order, name)
+				return next.Decide(request)
+			})
+		}
+	}
+
+	if _, err := Wrap(answering("approve"), tag("outer"), tag("inner")).Decide(eventRequest()); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("middleware ran in order %v, want [outer inner]", order)
+	}
+}
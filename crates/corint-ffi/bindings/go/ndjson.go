@@ -0,0 +1,120 @@
+package corint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// maxNDJSONLineBytes is the largest request line accepted by the NDJSON handler
+const maxNDJSONLineBytes = 4 << 20
+
+// NDJSONResult is one line of the NDJSON handler's response body
+type NDJSONResult struct {
+	// Index is the zero-based line number of the request in the body
+	Index    int               `json:"index"`
+	Response *DecisionResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// NDJSONHandler scores a streaming NDJSON body of DecisionRequests
+type NDJSONHandler struct {
+	engine  Engine
+	workers int
+}
+
+// NewNDJSONHandler returns a handler that decides each line of the request
+// body with up to workers concurrent decisions. Results are written as NDJSON
+// in completion order and flushed one by one; each carries the index of its
+// input line. Malformed lines produce an error result without stopping the stream.
+func NewNDJSONHandler(e Engine, workers int) *NDJSONHandler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &NDJSONHandler{engine: e, workers: workers}
+}
+
+// ServeHTTP implements http.Handler
+func (h *NDJSONHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	flusher, _ := w.(http.Flusher)
+	results := make(chan NDJSONResult)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		encoder := json.NewEncoder(w)
+		for result := range results {
+			if ctx.Err() != nil {
+				continue
+			}
+			if err := encoder.Encode(result); err != nil {
+				continue
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}()
+
+	slots := make(chan struct{}, h.workers)
+	var wg sync.WaitGroup
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLineBytes)
+
+	index := 0
+	for scanner.Scan() && ctx.Err() == nil {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		i := index
+		index++
+
+		var request DecisionRequest
+		if err := json.Unmarshal(line, &request); err != nil {
+			results <- NDJSONResult{Index: i, Error: "malformed request: " + err.Error()}
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			response, err := decideContext(ctx, h.engine, &request)
+			result := NDJSONResult{Index: i, Response: response}
+			if err != nil {
+				result = NDJSONResult{Index: i, Error: err.Error()}
+			}
+			results <- result
+		}()
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		results <- NDJSONResult{Index: index, Error: "read request body: " + err.Error()}
+	}
+
+	wg.Wait()
+	close(results)
+	<-writerDone
+}
@@ -0,0 +1,101 @@
+package corint
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONHandler(t *testing.T) {
+	engine := EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+		switch request.EventData["user_id"] {
+		case "u-fail":
+			return nil, errors.New("native runtime busy")
+		case "u-risky":
+			return decided("decline", "BLOCK"), nil
+		default:
+			return decided("approve"), nil
+		}
+	})
+	body := strings.Join([]string{
+		`{"event_data":{"user_id":"u-ok"}}`,
+		``,
+		`{"event_data":`,
+		`{"event_data":{"user_id":"u-risky"}}`,
+		`{"event_data":{"user_id":"u-fail"}}`,
+	}, "\n")
+
+	recorder := httptest.NewRecorder()
+	NewNDJSONHandler(engine, 2).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/decide", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var results []NDJSONResult
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var result NDJSONResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("decode result line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want one per non-empty line: %+v", len(results), results)
+	}
+	if r := results[0]; r.Error != "" || r.Response.Result.Signal.Type != "approve" {
+		t.Errorf("line 0 = %+v", r)
+	}
+	if r := results[1]; r.Response != nil || !strings.HasPrefix(r.Error, "malformed request: ") {
+		t.Errorf("malformed line = %+v", r)
+	}
+	if r := results[2]; r.Error != "" || r.Response.Result.Signal.Type != "decline" || r.Response.Result.Actions[0] != "BLOCK" {
+		t.Errorf("line 2 = %+v", r)
+	}
+	if r := results[3]; r.Response != nil || r.Error != "native runtime busy" {
+		t.Errorf("failing line = %+v", r)
+	}
+}
+
+func TestNDJSONHandlerRequiresPost(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewNDJSONHandler(answering("approve"), 1).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/decide", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("GET = %d with Allow %q, want 405 allowing POST", recorder.Code, recorder.Header().Get("Allow"))
+	}
+}
+
+func TestNDJSONHandlerAuthorizesWithRequestContext(t *testing.T) {
+	handler := NewNDJSONHandler(newFakeEngine(t, respondWith(approveResponse), WithAuthorizer(tenantAuthorizer)), 1)
+
+	for tenant, wantErr := range map[string]bool{"acme": false, "blocked": true} {
+		request := httptest.NewRequest(http.MethodPost, "/decide", strings.NewReader(`{"event_data":{"type":"login"}}`))
+		request = request.WithContext(context.WithValue(request.Context(), tenantKey{}, tenant))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		var result NDJSONResult
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			t.Fatalf("tenant %s: decode result: %v", tenant, err)
+		}
+		if gotErr := result.Error != ""; gotErr != wantErr {
+			t.Errorf("tenant %s: result = %+v, want error %v", tenant, result, wantErr)
+		}
+		if wantErr && !strings.Contains(result.Error, ErrUnauthorized.Error()) {
+			t.Errorf("tenant %s: error = %q, want it to report ErrUnauthorized", tenant, result.Error)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package corint
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// EngineOption configures optional DecisionEngine behavior
+type EngineOption func(*engineConfig)
+
+// engineConfig holds the settings applied by EngineOption values
+type engineConfig struct {
+	lenientResponses   bool
+	maxEventAge        time.Duration
+	maxTraceBytes      int
+	numberPolicy       NumberPolicy
+	diagnostics        bool
+	debugSampler       func(*DecisionRequest) bool
+	logger             *slog.Logger
+	redaction          *redaction
+	authorizer         func(context.Context, *DecisionRequest) error
+	emptyEventDecision string
+	featureStore       *featureEnrichment
+	featureFetchPolicy FeatureFetchPolicy
+	responseChunkSize  int
+	clearRuleToggles   bool
+	explainEndpoint    bool
+}
+
+// newEngineConfig applies opts on top of the default configuration
+func newEngineConfig(opts []EngineOption) engineConfig {
+	config := engineConfig{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithLenientResponses accepts native responses without a decision, including
+// empty ones, instead of failing with ErrEmptyResponse; they are returned with
+// an empty Decision. This restores the behavior of earlier releases.
+func WithLenientResponses() EngineOption {
+	return func(c *engineConfig) {
+		c.lenientResponses = true
+	}
+}
+
+// WithMaxEventAge rejects requests whose EventTime is older than d with ErrStaleEvent.
+// Requests without an EventTime are not checked.
+func WithMaxEventAge(d time.Duration) EngineOption {
+	return func(c *engineConfig) {
+		c.maxEventAge = d
+	}
+}
+
+// WithMaxTraceBytes has the native engine truncate traces larger than n bytes.
+// Truncated traces report Truncated() == true. Requests that set
+// DecisionOptions.MaxTraceBytes keep their own limit.
+func WithMaxTraceBytes(n int) EngineOption {
+	return func(c *engineConfig) {
+		c.maxTraceBytes = n
+	}
+}
+
+// WithNumberPolicy sets how NaN and infinite values in request data are handled.
+// The default is RejectInvalidNumbers.
+func WithNumberPolicy(policy NumberPolicy) EngineOption {
+	return func(c *engineConfig) {
+		c.numberPolicy = policy
+	}
+}
+
+// WithDiagnostics attaches Diagnostics to the DecisionError returned when the
+// native engine fails a decision
+func WithDiagnostics() EngineOption {
+	return func(c *engineConfig) {
+		c.diagnostics = true
+	}
+}
+
+// WithDebugSampler forces tracing for requests matching sample and logs their
+// traces, regardless of the request's own options. The trace is only returned
+// to the caller if the request enabled tracing itself.
+func WithDebugSampler(sample func(*DecisionRequest) bool) EngineOption {
+	return func(c *engineConfig) {
+		c.debugSampler = sample
+	}
+}
+
+// WithLogger sets the logger used for engine-level logging; the default is slog.Default()
+func WithLogger(logger *slog.Logger) EngineOption {
+	return func(c *engineConfig) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithRedaction redacts EventData fields in the copies of requests written to
+// engine logs and hashed into error diagnostics. Fields are dotted paths such
+// as "user.email"; a nil redactor uses MaskStrings. Decisions always see the
+// original values. Logged traces have every evaluated condition operand
+// passed through the redactor, and Diagnostics.InputHash is the hash of the
+// redacted request rather than of the request sent natively.
+func WithRedaction(fields []string, redactor func(interface{}) interface{}) EngineOption {
+	return func(c *engineConfig) {
+		c.redaction = newRedaction(fields, redactor)
+	}
+}
+
+// WithAuthorizer calls authorize before each decision; a non-nil error blocks
+// the decision and is returned wrapped in ErrUnauthorized. Decide and
+// DecideInRepository pass a background context; use DecideContext to supply
+// the caller's. The NDJSON and explain handlers pass the HTTP request's
+// context, and DecideWithRetry, AnyDeny, DecideBatchContext and
+// DecideStreamLimited pass the context they are given.
+func WithAuthorizer(authorize func(ctx context.Context, r *DecisionRequest) error) EngineOption {
+	return func(c *engineConfig) {
+		c.authorizer = authorize
+	}
+}
+
+// WithEmptyEventDecision returns decision without calling the native engine
+// for requests with empty EventData, instead of failing with ErrEmptyEventData
+func WithEmptyEventDecision(decision string) EngineOption {
+	return func(c *engineConfig) {
+		c.emptyEventDecision = decision
+	}
+}
+
+// WithClearRuleTogglesOnReload makes Reload drop toggles set with
+// SetRuleEnabled, so every rule of the reloaded state starts enabled
+func WithClearRuleTogglesOnReload() EngineOption {
+	return func(c *engineConfig) {
+		c.clearRuleToggles = true
+	}
+}
+
+// WithExplainEndpoint lets an ExplainHandler serve explanations for the
+// engine; without it the handler answers 404 Not Found
+func WithExplainEndpoint() EngineOption {
+	return func(c *engineConfig) {
+		c.explainEndpoint = true
+	}
+}
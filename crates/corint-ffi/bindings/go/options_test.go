@@ -0,0 +1,64 @@
+package corint
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDebugSamplerLogsTrace(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		var request struct {
+			Options DecisionOptions `json:"options"`
+		}
+		if err := json.Unmarshal(requestJSON, &request); err != nil {
+			return nil, err
+		}
+		if !request.Options.EnableTrace {
+			return []byte(approveResponse), nil
+		}
+		return []byte(fixtureResponseJSON), nil
+	}, WithLogger(logger), WithDebugSampler(func(request *DecisionRequest) bool {
+		return request.EventData["user_id"] == "u-debug"
+	}))
+
+	response, err := engine.Decide(&DecisionRequest{EventData: map[string]interface{}{"user_id": "u-debug"}})
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if response.Trace != nil {
+		t.Error("sampled trace was returned to a caller that did not enable tracing")
+	}
+
+	var entry struct {
+		Msg       string                 `json:"msg"`
+		RequestID string                 `json:"request_id"`
+		Decision  string                 `json:"decision"`
+		EventData map[string]interface{} `json:"event_data"`
+		Trace     Trace                  `json:"trace"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log entry %q: %v", logs.String(), err)
+	}
+	if entry.Msg != "sampled decision trace" || entry.RequestID != "req_fixture" || entry.Decision != "decline" {
+		t.Errorf("log entry = %+v", entry)
+	}
+	if entry.EventData["user_id"] != "u-debug" {
+		t.Errorf("logged event_data = %v", entry.EventData)
+	}
+	if evals := entry.Trace.RuleEvals(); len(evals) != 3 {
+		t.Errorf("logged trace has %d rules, want 3", len(evals))
+	}
+
+	logs.Reset()
+	if _, err := engine.Decide(&DecisionRequest{EventData: map[string]interface{}{"user_id": "u-other"}}); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if strings.Contains(logs.String(), "sampled decision trace") {
+		t.Errorf("unsampled request was logged: %s", logs.String())
+	}
+}
@@ -0,0 +1,109 @@
+package corint
+
+import "strings"
+
+// redactedMask replaces string values masked by MaskStrings
+const redactedMask = "[REDACTED]"
+
+// MaskStrings is the default redactor. It replaces strings with a fixed mask
+// and leaves other values unchanged.
+func MaskStrings(value interface{}) interface{} {
+	if _, ok := value.(string); ok {
+		return redactedMask
+	}
+	return value
+}
+
+// redaction rewrites configured EventData fields in copies of requests
+type redaction struct {
+	paths    [][]string
+	redactor func(interface{}) interface{}
+}
+
+// newRedaction parses dotted field paths such as "user.email"
+func newRedaction(fields []string, redactor func(interface{}) interface{}) *redaction {
+	if redactor == nil {
+		redactor = MaskStrings
+	}
+	r := &redaction{redactor: redactor}
+	for _, field := range fields {
+		if field != "" {
+			r.paths = append(r.paths, strings.Split(field, "."))
+		}
+	}
+	return r
+}
+
+// apply returns a copy of request with the configured fields redacted.
+// Maps along a redacted path are copied, everything else is shared with request.
+func (r *redaction) apply(request *DecisionRequest) *DecisionRequest {
+	if r == nil || request == nil {
+		return request
+	}
+
+	redacted := *request
+	for _, path := range r.paths {
+		redacted.EventData = r.redactPath(redacted.EventData, path)
+	}
+	return &redacted
+}
+
+// redactPath returns a copy of data with the value at path redacted, or data
+// itself when the path is not present
+func (r *redaction) redactPath(data map[string]interface{}, path []string) map[string]interface{} {
+	value, ok := data[path[0]]
+	if !ok {
+		return data
+	}
+
+	if len(path) == 1 {
+		value = r.redactor(value)
+	} else {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return data
+		}
+		value = r.redactPath(nested, path[1:])
+	}
+
+	copied := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+	copied[path[0]] = value
+	return copied
+}
+
+// traceOperands are the trace keys holding evaluated condition operands
+var traceOperands = []string{"left_value", "right_value"}
+
+// applyTrace returns a copy of trace with every evaluated condition operand
+// passed through the redactor. Operands are not tied to field paths, so all of
+// them are redacted, not only those of the configured fields.
+func (r *redaction) applyTrace(trace Trace) Trace {
+	if r == nil || trace == nil {
+		return trace
+	}
+	redacted := trace.Clone()
+	r.redactOperands(map[string]interface{}(redacted))
+	return redacted
+}
+
+// redactOperands redacts operands in place throughout a cloned trace value
+func (r *redaction) redactOperands(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range traceOperands {
+			if operand, ok := v[key]; ok {
+				v[key] = r.redactor(operand)
+			}
+		}
+		for _, item := range v {
+			r.redactOperands(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			r.redactOperands(item)
+		}
+	}
+}
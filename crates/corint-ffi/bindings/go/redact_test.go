@@ -0,0 +1,104 @@
+package corint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// emailRequest returns a request carrying an email to redact
+func emailRequest() *DecisionRequest {
+	return &DecisionRequest{EventData: map[string]interface{}{
+		"type": "login",
+		"user": map[string]interface{}{"email": "alice@example.com", "id": "u-1"},
+	}}
+}
+
+func TestRedactionInSampledLog(t *testing.T) {
+	var sent []byte
+	var logs bytes.Buffer
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		sent = requestJSON
+		return []byte(fixtureResponseJSON), nil
+	},
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+		WithDebugSampler(func(*DecisionRequest) bool { return true }),
+		WithRedaction([]string{"user.email"}, nil))
+
+	request := emailRequest()
+	if _, err := engine.Decide(request); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+
+	if !bytes.Contains(sent, []byte("alice@example.com")) {
+		t.Errorf("native request %s does not carry the original email", sent)
+	}
+	if request.EventData["user"].(map[string]interface{})["email"] != "alice@example.com" {
+		t.Error("caller's request was redacted")
+	}
+
+	logged := logs.String()
+	if strings.Contains(logged, "alice@example.com") {
+		t.Errorf("log contains the email: %s", logged)
+	}
+	var entry struct {
+		EventData map[string]interface{} `json:"event_data"`
+		Trace     Trace                  `json:"trace"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log entry: %v", err)
+	}
+	if user := entry.EventData["user"].(map[string]interface{}); user["email"] != redactedMask || user["id"] != "u-1" {
+		t.Errorf("logged user = %v, want only the email redacted", user)
+	}
+	for _, rule := range entry.Trace.RuleEvals() {
+		for _, condition := range rule.Conditions {
+			for _, leaf := range condition.Leaves() {
+				if s, ok := leaf.LeftValue.(string); ok && s != redactedMask {
+					t.Errorf("logged operand %q of %s is not redacted", s, leaf.Expression)
+				}
+				if s, ok := leaf.RightValue.(string); ok && s != redactedMask {
+					t.Errorf("logged operand %q of %s is not redacted", s, leaf.Expression)
+				}
+			}
+		}
+	}
+}
+
+func TestRedactionInDiagnostics(t *testing.T) {
+	var sent []byte
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		sent = requestJSON
+		return []byte(compileFailure), nil
+	}, WithDiagnostics(), WithRedaction([]string{"user.email"}, nil))
+
+	_, err := engine.Decide(emailRequest())
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) || decisionErr.Diagnostics == nil {
+		t.Fatalf("Decide() error = %#v, want diagnostics", err)
+	}
+
+	if !bytes.Contains(sent, []byte("alice@example.com")) {
+		t.Errorf("native request %s does not carry the original email", sent)
+	}
+	redacted := bytes.Replace(sent, []byte("alice@example.com"), []byte(redactedMask), 1)
+	if got, want := decisionErr.Diagnostics.InputHash, inputHash(redacted); got != want {
+		t.Errorf("InputHash = %s, want the hash of the redacted request %s", got, want)
+	}
+}
+
+func TestRedactionTraceKeepsOriginal(t *testing.T) {
+	trace := fixtureResponse(t).Trace
+	redacted := newRedaction(nil, nil).applyTrace(trace)
+
+	leaves := trace.RuleEvals()[1].Conditions[0].Leaves()
+	if leaves[1].LeftValue != "DE" {
+		t.Errorf("original operand = %v, want DE", leaves[1].LeftValue)
+	}
+	if got := redacted.RuleEvals()[1].Conditions[0].Leaves()[1].LeftValue; got != redactedMask {
+		t.Errorf("redacted operand = %v, want %s", got, redactedMask)
+	}
+}
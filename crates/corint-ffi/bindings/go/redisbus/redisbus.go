@@ -0,0 +1,80 @@
+// Package redisbus implements corint.CacheInvalidationBus over Redis pub/sub.
+//
+// It is a separate module, so only services that broadcast cache
+// invalidations through Redis depend on a Redis client.
+package redisbus
+
+import (
+	"context"
+	"sync"
+
+	corint "github.com/corint/corint-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultChannel is the pub/sub channel used when New is given an empty channel
+const DefaultChannel = "corint:cache:invalidate"
+
+// invalidateMessage is the payload of a published invalidation
+const invalidateMessage = "invalidate"
+
+// Bus publishes and receives cache invalidations on a Redis channel
+type Bus struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+var _ corint.CacheInvalidationBus = (*Bus)(nil)
+
+// New returns a Bus on channel, or on DefaultChannel when channel is empty.
+// Every instance sharing a cache must use the same channel.
+func New(client redis.UniversalClient, channel string) *Bus {
+	if channel == "" {
+		channel = DefaultChannel
+	}
+	return &Bus{client: client, channel: channel}
+}
+
+// Publish announces that cached decisions are stale
+func (b *Bus) Publish(ctx context.Context) error {
+	return b.client.Publish(ctx, b.channel, invalidateMessage).Err()
+}
+
+// Subscribe calls fn for every invalidation published on the channel until
+// the returned function is called or ctx is done. It returns once Redis has
+// confirmed the subscription, so a Publish made afterwards is not missed.
+func (b *Bus) Subscribe(ctx context.Context, fn func()) (unsubscribe func(), err error) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			close(done)
+			pubsub.Close()
+		})
+	}
+
+	messages := pubsub.Channel()
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				unsubscribe()
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+				fn()
+			}
+		}
+	}()
+	return unsubscribe, nil
+}
@@ -0,0 +1,85 @@
+package redisbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestBus returns a Bus on an in-memory Redis server
+func newTestBus(t *testing.T) *Bus {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "")
+}
+
+// waitFor fails the test unless a value arrives on ch within a second
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestPublishReachesSubscribers(t *testing.T) {
+	bus := newTestBus(t)
+	ctx := context.Background()
+
+	first, second := make(chan struct{}, 1), make(chan struct{}, 1)
+	for _, ch := range []chan struct{}{first, second} {
+		ch := ch
+		unsubscribe, err := bus.Subscribe(ctx, func() { ch <- struct{}{} })
+		if err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+		defer unsubscribe()
+	}
+
+	if err := bus.Publish(ctx); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	waitFor(t, first, "the first subscriber")
+	waitFor(t, second, "the second subscriber")
+}
+
+func TestUnsubscribeStopsDelivery(t *testing.T) {
+	bus := newTestBus(t)
+	ctx := context.Background()
+
+	received := make(chan struct{}, 2)
+	unsubscribe, err := bus.Subscribe(ctx, func() { received <- struct{}{} })
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	unsubscribe()
+	unsubscribe()
+
+	if err := bus.Publish(ctx); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case <-received:
+		t.Error("received an invalidation after unsubscribe")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscribeFailsWhenRedisIsDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := New(client, "").Subscribe(ctx, func() {}); err == nil {
+		t.Error("Subscribe succeeded against a stopped server")
+	}
+}
@@ -0,0 +1,68 @@
+package corint
+
+/*
+int corint_engine_reload_with_options(void* engine, int clear_rule_toggles);
+*/
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrReloadUnsupported is returned when reloading an engine that serves several named repositories
+var ErrReloadUnsupported = errors.New("engine does not support reload")
+
+// Reload rebuilds the engine from its repository without interrupting
+// decisions. Decisions started before the swap finish on the previous state;
+// decisions started after it use the reloaded one. If reloading fails the
+// current state is kept. Rules disabled with SetRuleEnabled stay disabled
+// unless the engine was created with WithClearRuleTogglesOnReload, which
+// still keeps toggles set while the reload runs.
+func (e *DecisionEngine) Reload() error {
+	if e.handle == nil {
+		return ErrEngineClosed
+	}
+
+	e.togglesMu.Lock()
+	if e.reloads == 0 {
+		e.reloadToggles = make(map[string]bool)
+	}
+	e.reloads++
+	e.togglesMu.Unlock()
+
+	status := nativeReload(e.handle, e.config.clearRuleToggles)
+
+	// A clearing reload drops toggles set on the previous state while the new
+	// one was built, so set them again on the reloaded state
+	e.togglesMu.Lock()
+	e.reloads--
+	if status == 0 && e.config.clearRuleToggles {
+		for ruleID, enabled := range e.reloadToggles {
+			nativeSetRuleEnabled(e.handle, ruleID, enabled)
+		}
+	}
+	if e.reloads == 0 {
+		e.reloadToggles = nil
+	}
+	e.togglesMu.Unlock()
+
+	switch status {
+	case 0:
+		return nil
+	case -2:
+		return ErrReloadUnsupported
+	default:
+		return errors.New("failed to reload decision engine")
+	}
+}
+
+// nativeReload rebuilds the state of a native engine handle, dropping rule
+// toggles if clearRuleToggles is set, and returns the native status code;
+// tests replace it to fake reloads
+var nativeReload = func(handle unsafe.Pointer, clearRuleToggles bool) int {
+	var cClear C.int
+	if clearRuleToggles {
+		cClear = 1
+	}
+	return int(C.corint_engine_reload_with_options(handle, cClear))
+}
@@ -0,0 +1,233 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// fakeReload makes native reloads return status, running swap first on success
+func fakeReload(t *testing.T, status int, swap func()) {
+	t.Helper()
+	original := nativeReload
+	nativeReload = func(unsafe.Pointer, bool) int {
+		if status == 0 && swap != nil {
+			swap()
+		}
+		return status
+	}
+	t.Cleanup(func() { nativeReload = original })
+}
+
+func TestReloadDuringDecisions(t *testing.T) {
+	var state atomic.Pointer[string]
+	decline := "decline"
+	state.Store(&decline)
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		return []byte(fmt.Sprintf(`{"request_id":"req_1","result":{"signal":{"type":%q},"actions":[],"score":0,"triggered_rules":[],"explanation":"","context":{}},"processing_time_ms":1}`, *state.Load())), nil
+	})
+	review := "review"
+	fakeReload(t, 0, func() { state.Store(&review) })
+
+	var (
+		wg   sync.WaitGroup
+		stop atomic.Bool
+		errs = make(chan error, 8)
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				response, err := engine.Decide(eventRequest())
+				if err != nil {
+					errs <- err
+					return
+				}
+				if response.Decision != "decline" && response.Decision != "review" {
+					errs <- fmt.Errorf("unexpected decision %q", response.Decision)
+					return
+				}
+			}
+		}()
+	}
+
+	if err := engine.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		response, err := engine.Decide(eventRequest())
+		if err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+		if response.Decision != "review" {
+			t.Fatalf("Decide() after Reload = %q, want review", response.Decision)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("decision during reload: %v", err)
+	}
+}
+
+func TestReloadStatus(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse))
+
+	fakeReload(t, -2, nil)
+	if err := engine.Reload(); !errors.Is(err, ErrReloadUnsupported) {
+		t.Errorf("Reload() error = %v, want ErrReloadUnsupported", err)
+	}
+
+	fakeReload(t, -1, nil)
+	if err := engine.Reload(); err == nil {
+		t.Error("Reload() succeeded on a failed native reload")
+	}
+
+	closed := &DecisionEngine{}
+	if err := closed.Reload(); !errors.Is(err, ErrEngineClosed) {
+		t.Errorf("Reload() on a closed engine = %v, want ErrEngineClosed", err)
+	}
+}
+
+// fakeToggles is a fake native rule toggle state over the rule IDs it knows
+type fakeToggles struct {
+	mu       sync.Mutex
+	known    map[string]bool
+	disabled map[string]bool
+	cleared  []bool
+}
+
+// fakeRuleToggles replaces native reloads and rule toggles with a fake
+// engine knowing the given rules
+func fakeRuleToggles(t *testing.T, rules ...string) *fakeToggles {
+	t.Helper()
+	toggles := &fakeToggles{known: map[string]bool{}, disabled: map[string]bool{}}
+	for _, rule := range rules {
+		toggles.known[rule] = true
+	}
+
+	originalReload, originalSet := nativeReload, nativeSetRuleEnabled
+	nativeReload = func(_ unsafe.Pointer, clearRuleToggles bool) int {
+		toggles.mu.Lock()
+		defer toggles.mu.Unlock()
+		toggles.cleared = append(toggles.cleared, clearRuleToggles)
+		if clearRuleToggles {
+			toggles.disabled = map[string]bool{}
+		}
+		return 0
+	}
+	nativeSetRuleEnabled = func(_ unsafe.Pointer, ruleID string, enabled bool) int {
+		toggles.mu.Lock()
+		defer toggles.mu.Unlock()
+		if !toggles.known[ruleID] {
+			return -2
+		}
+		toggles.disabled[ruleID] = !enabled
+		return 0
+	}
+	t.Cleanup(func() { nativeReload, nativeSetRuleEnabled = originalReload, originalSet })
+	return toggles
+}
+
+// isDisabled reports whether the fake native state has ruleID disabled
+func (f *fakeToggles) isDisabled(ruleID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.disabled[ruleID]
+}
+
+func TestSetRuleEnabled(t *testing.T) {
+	toggles := fakeRuleToggles(t, "high_amount")
+	engine := newFakeEngine(t, respondWith(approveResponse))
+
+	if err := engine.SetRuleEnabled("high_amount", false); err != nil {
+		t.Fatalf("SetRuleEnabled: %v", err)
+	}
+	if !toggles.isDisabled("high_amount") {
+		t.Error("high_amount is not disabled natively")
+	}
+	if err := engine.SetRuleEnabled("missing", false); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("SetRuleEnabled(missing) error = %v, want ErrRuleNotFound", err)
+	}
+
+	closed := &DecisionEngine{}
+	if err := closed.SetRuleEnabled("high_amount", false); !errors.Is(err, ErrEngineClosed) {
+		t.Errorf("SetRuleEnabled() on a closed engine = %v, want ErrEngineClosed", err)
+	}
+}
+
+func TestReloadRuleToggles(t *testing.T) {
+	for _, clearToggles := range []bool{false, true} {
+		t.Run(fmt.Sprintf("clear=%v", clearToggles), func(t *testing.T) {
+			toggles := fakeRuleToggles(t, "high_amount")
+			var opts []EngineOption
+			if clearToggles {
+				opts = append(opts, WithClearRuleTogglesOnReload())
+			}
+			engine := newFakeEngine(t, respondWith(approveResponse), opts...)
+
+			if err := engine.SetRuleEnabled("high_amount", false); err != nil {
+				t.Fatalf("SetRuleEnabled: %v", err)
+			}
+			if err := engine.Reload(); err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+			if len(toggles.cleared) != 1 || toggles.cleared[0] != clearToggles {
+				t.Errorf("native reloads cleared toggles = %v, want [%v]", toggles.cleared, clearToggles)
+			}
+			if got := toggles.isDisabled("high_amount"); got == clearToggles {
+				t.Errorf("high_amount disabled after reload = %v, want %v", got, !clearToggles)
+			}
+		})
+	}
+}
+
+func TestSetRuleEnabledDuringReload(t *testing.T) {
+	for _, clearToggles := range []bool{false, true} {
+		t.Run(fmt.Sprintf("clear toggles %v", clearToggles), func(t *testing.T) {
+			toggles := fakeRuleToggles(t, "high_amount")
+			var opts []EngineOption
+			if clearToggles {
+				opts = append(opts, WithClearRuleTogglesOnReload())
+			}
+			engine := newFakeEngine(t, respondWith(approveResponse), opts...)
+
+			reloading, release := make(chan struct{}), make(chan struct{})
+			togglingReload := nativeReload
+			nativeReload = func(handle unsafe.Pointer, clearRuleToggles bool) int {
+				close(reloading)
+				<-release
+				return togglingReload(handle, clearRuleToggles)
+			}
+
+			reloaded := make(chan error, 1)
+			go func() { reloaded <- engine.Reload() }()
+			<-reloading
+
+			toggled := make(chan error, 1)
+			go func() { toggled <- engine.SetRuleEnabled("high_amount", false) }()
+			select {
+			case err := <-toggled:
+				if err != nil {
+					t.Fatalf("SetRuleEnabled: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("SetRuleEnabled blocked on a reload in progress")
+			}
+
+			close(release)
+			if err := <-reloaded; err != nil {
+				t.Fatalf("Reload: %v", err)
+			}
+			if !toggles.isDisabled("high_amount") {
+				t.Error("toggle set during the reload was lost")
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package corint
+
+/*
+#include <stdlib.h>
+
+void* corint_engine_new_multi(const char* repositories_json);
+char* corint_engine_decide_in_repository(void* engine, const char* repository, const char* request_json);
+*/
+import "C"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// NewEngineWithRepositories creates an engine serving several file system
+// repositories addressed by name. Decide uses the repository named "default"
+// if present, and otherwise the first name in lexicographic order.
+func NewEngineWithRepositories(repositoryPaths map[string]string, opts ...EngineOption) (*DecisionEngine, error) {
+	if err := CheckABI(); err != nil {
+		return nil, err
+	}
+
+	if len(repositoryPaths) == 0 {
+		return nil, errors.New("at least one repository is required")
+	}
+
+	pathsJSON, err := json.Marshal(repositoryPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	cPaths := C.CString(string(pathsJSON))
+	defer C.free(unsafe.Pointer(cPaths))
+
+	handle := C.corint_engine_new_multi(cPaths)
+	if handle == nil {
+		return nil, errors.New("failed to create multi-repository decision engine")
+	}
+
+	return &DecisionEngine{handle: handle, config: newEngineConfig(opts)}, nil
+}
+
+// DecideInRepository executes a decision against the named repository of an
+// engine created with NewEngineWithRepositories. Unknown names fail with
+// ErrUnknownRepository.
+func (e *DecisionEngine) DecideInRepository(repository string, request *DecisionRequest) (*DecisionResponse, error) {
+	return e.decide(context.Background(), request, func(requestJSON []byte) (io.ReadCloser, error) {
+		return wholeResponse(nativeDecideInRepository(e.handle, repository, requestJSON))
+	})
+}
+
+// nativeDecideInRepository executes requestJSON against a named repository of
+// a native engine handle; tests replace it to fake native responses
+var nativeDecideInRepository = func(handle unsafe.Pointer, repository string, requestJSON []byte) ([]byte, error) {
+	cRepository := C.CString(repository)
+	defer C.free(unsafe.Pointer(cRepository))
+	cRequest := C.CString(string(requestJSON))
+	defer C.free(unsafe.Pointer(cRequest))
+	return nativeResult(C.corint_engine_decide_in_repository(handle, cRepository, cRequest))
+}
@@ -0,0 +1,51 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+// fakeRepositories answers DecideInRepository for the named repositories with
+// their decision, and with the native unknown_repository error otherwise
+func fakeRepositories(t *testing.T, decisions map[string]string) *DecisionEngine {
+	t.Helper()
+	original := nativeDecideInRepository
+	nativeDecideInRepository = func(_ unsafe.Pointer, repository string, _ []byte) ([]byte, error) {
+		decision, ok := decisions[repository]
+		if !ok {
+			return []byte(fmt.Sprintf(`{"error":"unknown repository: %s","error_code":"unknown_repository","success":false}`, repository)), nil
+		}
+		return []byte(fmt.Sprintf(`{"request_id":"req_%s","result":{"signal":{"type":%q},"actions":[],"score":0,"triggered_rules":[],"explanation":"","context":{}},"processing_time_ms":1}`, repository, decision)), nil
+	}
+	t.Cleanup(func() { nativeDecideInRepository = original })
+	return &DecisionEngine{handle: unsafe.Pointer(&testHandle), config: newEngineConfig(nil)}
+}
+
+func TestDecideInRepository(t *testing.T) {
+	engine := fakeRepositories(t, map[string]string{"payments": "decline", "login": "approve"})
+
+	for repository, want := range map[string]string{"payments": "decline", "login": "approve"} {
+		response, err := engine.DecideInRepository(repository, eventRequest())
+		if err != nil {
+			t.Fatalf("DecideInRepository(%q): %v", repository, err)
+		}
+		if response.Decision != want {
+			t.Errorf("DecideInRepository(%q).Decision = %q, want %q", repository, response.Decision, want)
+		}
+	}
+}
+
+func TestDecideInRepositoryUnknown(t *testing.T) {
+	engine := fakeRepositories(t, map[string]string{"payments": "decline"})
+
+	_, err := engine.DecideInRepository("missing", eventRequest())
+	if !errors.Is(err, ErrUnknownRepository) {
+		t.Fatalf("DecideInRepository() error = %v, want ErrUnknownRepository", err)
+	}
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) || decisionErr.Code != "unknown_repository" {
+		t.Errorf("error = %#v, want a DecisionError with code unknown_repository", err)
+	}
+}
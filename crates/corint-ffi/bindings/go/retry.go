@@ -0,0 +1,210 @@
+package corint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ErrInvalidDatabaseURL is returned when a database URL can never succeed, so retrying is pointless
+var ErrInvalidDatabaseURL = errors.New("invalid database URL")
+
+// ErrInvalidRetryPolicy is returned by DecideWithRetry for a policy without an attempt limit
+var ErrInvalidRetryPolicy = errors.New("retry policy must limit the number of attempts")
+
+// minRetryBackoff is the shortest wait between decision attempts, so a zero
+// backoff cannot turn DecideWithRetry into a busy loop
+const minRetryBackoff = time.Millisecond
+
+// RetryPolicy controls how engine creation is retried
+type RetryPolicy struct {
+	// MaxAttempts limits the number of attempts. For engine creation 0 retries
+	// until the context expires; DecideWithRetry requires a positive limit.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts; 0 means no cap
+	MaxBackoff time.Duration
+	// Multiplier scales the backoff after each failed attempt; values below 1 keep it constant
+	Multiplier float64
+	// CategoryBackoff overrides the backoff for specific error categories in
+	// DecideWithRetry; waits shorter than 1ms are raised to 1ms
+	CategoryBackoff map[ErrorCategory]time.Duration
+	// Classify categorizes decision errors for DecideWithRetry; nil uses ClassifyError
+	Classify func(error) ErrorCategory
+}
+
+// ErrorCategory classifies decision errors for retrying
+type ErrorCategory int
+
+const (
+	// TransientError may succeed when retried
+	TransientError ErrorCategory = iota
+	// RateLimitedError should be retried after the hinted delay
+	RateLimitedError
+	// PermanentError will fail again and is not retried
+	PermanentError
+)
+
+// ClassifyError is the default error classification used by DecideWithRetry.
+// Invalid input, authorizer denials, missing rules, empty native responses
+// and engine configuration problems are permanent, errors with a retry hint
+// are rate limited, and everything else is transient.
+func ClassifyError(err error) ErrorCategory {
+	if errors.Is(err, ErrInvalidNumber) || errors.Is(err, ErrStaleEvent) ||
+		errors.Is(err, ErrUnknownRepository) || errors.Is(err, ErrEngineClosed) ||
+		errors.Is(err, ErrInvalidOptions) || errors.Is(err, ErrEmptyEventData) ||
+		errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrRuleNotFound) ||
+		errors.Is(err, ErrEmptyResponse) {
+		return PermanentError
+	}
+
+	var decisionErr *DecisionError
+	if errors.As(err, &decisionErr) {
+		if decisionErr.RetryAfter > 0 || decisionErr.Code == "rate_limited" {
+			return RateLimitedError
+		}
+		switch decisionErr.Kind {
+		case "config", "parse", "compile", "invalid_rule_file":
+			return PermanentError
+		}
+	}
+	return TransientError
+}
+
+// DefaultRetryPolicy retries with exponential backoff from 100ms up to 5s
+var DefaultRetryPolicy = RetryPolicy{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (p RetryPolicy) backoff(failures int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < failures && p.Multiplier > 1; i++ {
+		wait = time.Duration(float64(wait) * p.Multiplier)
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// newEngineFromDatabase creates an engine for a single attempt
+var newEngineFromDatabase = NewEngineFromDatabase
+
+// NewEngineFromDatabaseWithRetry creates a database-backed engine, retrying
+// transient failures (e.g. the database not being up yet) according to policy
+// until creation succeeds, the attempts run out or ctx expires. Malformed URLs
+// fail immediately with ErrInvalidDatabaseURL, and an incompatible native
+// library with ErrABIMismatch.
+func NewEngineFromDatabaseWithRetry(ctx context.Context, databaseURL string, policy RetryPolicy, opts ...EngineOption) (*DecisionEngine, error) {
+	if err := validateDatabaseURL(databaseURL); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+			return nil, err
+		}
+
+		engine, err := newEngineFromDatabase(databaseURL, opts...)
+		if err == nil {
+			return engine, nil
+		}
+		if errors.Is(err, ErrABIMismatch) {
+			return nil, err
+		}
+		lastErr = err
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return nil, fmt.Errorf("engine creation failed after %d attempts: %w", attempt, lastErr)
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// validateDatabaseURL rejects URLs the native repository cannot connect to
+func validateDatabaseURL(databaseURL string) error {
+	parsed, err := url.Parse(databaseURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDatabaseURL, err)
+	}
+	switch parsed.Scheme {
+	case "postgres", "postgresql":
+	default:
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidDatabaseURL, parsed.Scheme)
+	}
+	return nil
+}
+
+// DecideWithRetry executes a decision, retrying failures according to policy
+// until it succeeds, a permanent error occurs, the attempts run out or ctx
+// expires. Engines implementing ContextEngine decide with ctx. Rate-limited errors wait for the DecisionError.RetryAfter hint
+// when the native engine provides one. Policies with a non-positive
+// MaxAttempts fail with ErrInvalidRetryPolicy.
+func DecideWithRetry(ctx context.Context, e Engine, request *DecisionRequest, policy RetryPolicy) (*DecisionResponse, error) {
+	if policy.MaxAttempts <= 0 {
+		return nil, ErrInvalidRetryPolicy
+	}
+	classify := policy.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		response, err := decideContext(ctx, e, request)
+		if err == nil {
+			return response, nil
+		}
+
+		category := classify(err)
+		if category == PermanentError {
+			return nil, err
+		}
+		if attempt >= policy.MaxAttempts {
+			return nil, fmt.Errorf("decision failed after %d attempts: %w", attempt, err)
+		}
+
+		wait, ok := policy.CategoryBackoff[category]
+		if !ok {
+			wait = policy.backoff(attempt)
+		}
+		var decisionErr *DecisionError
+		if category == RateLimitedError && errors.As(err, &decisionErr) && decisionErr.RetryAfter > 0 {
+			wait = decisionErr.RetryAfter
+		}
+
+		if wait < minRetryBackoff {
+			wait = minRetryBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package corint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy retries quickly so tests do not wait on real backoff
+var fastRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     2 * time.Millisecond,
+	Multiplier:     2,
+}
+
+// fakeEngineFactory replaces engine creation for the rest of the test. The
+// factory fails the first failures calls and then succeeds.
+func fakeEngineFactory(t *testing.T, failures int) *int {
+	t.Helper()
+	original := newEngineFromDatabase
+	calls := 0
+	newEngineFromDatabase = func(string, ...EngineOption) (*DecisionEngine, error) {
+		calls++
+		if calls <= failures {
+			return nil, errors.New("connection refused")
+		}
+		return &DecisionEngine{}, nil
+	}
+	t.Cleanup(func() { newEngineFromDatabase = original })
+	return &calls
+}
+
+func TestNewEngineFromDatabaseWithRetryEventuallySucceeds(t *testing.T) {
+	calls := fakeEngineFactory(t, 2)
+
+	engine, err := NewEngineFromDatabaseWithRetry(context.Background(), "postgres://localhost/corint", fastRetryPolicy)
+	if err != nil {
+		t.Fatalf("NewEngineFromDatabaseWithRetry: %v", err)
+	}
+	if engine == nil {
+		t.Fatal("no engine returned")
+	}
+	if *calls != 3 {
+		t.Fatalf("factory called %d times, want 3", *calls)
+	}
+}
+
+func TestNewEngineFromDatabaseWithRetryPermanentError(t *testing.T) {
+	calls := fakeEngineFactory(t, 0)
+
+	for _, url := range []string{"mysql://localhost/corint", "://bad"} {
+		_, err := NewEngineFromDatabaseWithRetry(context.Background(), url, fastRetryPolicy)
+		if !errors.Is(err, ErrInvalidDatabaseURL) {
+			t.Errorf("%q: error = %v, want ErrInvalidDatabaseURL", url, err)
+		}
+	}
+	if *calls != 0 {
+		t.Fatalf("factory called %d times for invalid URLs, want 0", *calls)
+	}
+}
+
+func TestNewEngineFromDatabaseWithRetryAttemptsExhausted(t *testing.T) {
+	calls := fakeEngineFactory(t, 10)
+
+	_, err := NewEngineFromDatabaseWithRetry(context.Background(), "postgresql://localhost/corint", fastRetryPolicy)
+	if err == nil {
+		t.Fatal("expected an error once attempts run out")
+	}
+	if *calls != fastRetryPolicy.MaxAttempts {
+		t.Fatalf("factory called %d times, want %d", *calls, fastRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestNewEngineFromDatabaseWithRetryContextExpiry(t *testing.T) {
+	fakeEngineFactory(t, 1000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	policy := fastRetryPolicy
+	policy.MaxAttempts = 0
+
+	_, err := NewEngineFromDatabaseWithRetry(ctx, "postgres://localhost/corint", policy)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// failingEngine fails the first failures decisions with err and then approves
+func failingEngine(failures int, err error) (Engine, *int) {
+	calls := 0
+	return EngineFunc(func(*DecisionRequest) (*DecisionResponse, error) {
+		calls++
+		if calls <= failures {
+			return nil, err
+		}
+		return decided("approve"), nil
+	}), &calls
+}
+
+func TestDecideWithRetryWaitsForRetryAfter(t *testing.T) {
+	rateLimited := &DecisionError{Message: "rate limited", Code: "rate_limited", RetryAfter: 30 * time.Millisecond}
+	engine, calls := failingEngine(1, rateLimited)
+
+	start := time.Now()
+	response, err := DecideWithRetry(context.Background(), engine, eventRequest(), fastRetryPolicy)
+	if err != nil || response.Decision != "approve" {
+		t.Fatalf("DecideWithRetry() = %v, %v", response, err)
+	}
+	if *calls != 2 {
+		t.Errorf("engine called %d times, want 2", *calls)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("retried after %v, before the 30ms RetryAfter hint", elapsed)
+	}
+}
+
+func TestDecideWithRetryCompileErrorIsPermanent(t *testing.T) {
+	native := newFakeEngine(t, respondWith(`{"error":"compile failed","success":false,"diagnostics":{"error_kind":"compile"}}`))
+	calls := 0
+	engine := EngineFunc(func(request *DecisionRequest) (*DecisionResponse, error) {
+		calls++
+		return native.Decide(request)
+	})
+
+	_, err := DecideWithRetry(context.Background(), engine, eventRequest(), fastRetryPolicy)
+	var decisionErr *DecisionError
+	if !errors.As(err, &decisionErr) || decisionErr.Kind != "compile" {
+		t.Fatalf("DecideWithRetry() error = %#v, want a compile DecisionError", err)
+	}
+	if decisionErr.Diagnostics != nil {
+		t.Error("Diagnostics set without WithDiagnostics")
+	}
+	if calls != 1 {
+		t.Errorf("engine called %d times, want 1 for a permanent error", calls)
+	}
+}
+
+func TestDecideWithRetryTransientErrors(t *testing.T) {
+	engine, calls := failingEngine(2, errors.New("native runtime busy"))
+	policy := fastRetryPolicy
+	policy.CategoryBackoff = map[ErrorCategory]time.Duration{TransientError: 0}
+
+	response, err := DecideWithRetry(context.Background(), engine, eventRequest(), policy)
+	if err != nil || response.Decision != "approve" {
+		t.Fatalf("DecideWithRetry() = %v, %v", response, err)
+	}
+	if *calls != 3 {
+		t.Errorf("engine called %d times, want 3", *calls)
+	}
+
+	engine, calls = failingEngine(10, errors.New("native runtime busy"))
+	if _, err := DecideWithRetry(context.Background(), engine, eventRequest(), policy); err == nil {
+		t.Fatal("DecideWithRetry succeeded after exhausting attempts")
+	}
+	if *calls != policy.MaxAttempts {
+		t.Errorf("engine called %d times, want MaxAttempts %d", *calls, policy.MaxAttempts)
+	}
+}
+
+func TestDecideWithRetryRejectsZeroPolicy(t *testing.T) {
+	engine, calls := failingEngine(0, nil)
+	if _, err := DecideWithRetry(context.Background(), engine, eventRequest(), RetryPolicy{}); !errors.Is(err, ErrInvalidRetryPolicy) {
+		t.Fatalf("DecideWithRetry() error = %v, want ErrInvalidRetryPolicy", err)
+	}
+	if *calls != 0 {
+		t.Errorf("engine called %d times for an invalid policy", *calls)
+	}
+}
+
+func TestClassifyErrorPermanent(t *testing.T) {
+	for _, err := range []error{
+		fmt.Errorf("%w: tenant blocked", ErrUnauthorized),
+		ErrRuleNotFound,
+		ErrEmptyResponse,
+		ErrEmptyEventData,
+	} {
+		if got := ClassifyError(err); got != PermanentError {
+			t.Errorf("ClassifyError(%v) = %v, want PermanentError", err, got)
+		}
+	}
+	if got := ClassifyError(errors.New("native runtime busy")); got != TransientError {
+		t.Errorf("ClassifyError(runtime) = %v, want TransientError", got)
+	}
+}
+
+func TestDecideWithRetryUnauthorizedIsPermanent(t *testing.T) {
+	engine, calls := failingEngine(10, fmt.Errorf("%w: tenant blocked", ErrUnauthorized))
+	if _, err := DecideWithRetry(context.Background(), engine, eventRequest(), fastRetryPolicy); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("DecideWithRetry() error = %v, want ErrUnauthorized", err)
+	}
+	if *calls != 1 {
+		t.Errorf("engine called %d times, want a single attempt", *calls)
+	}
+}
+
+func TestDecideWithRetryPassesContext(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse), WithAuthorizer(tenantAuthorizer))
+	blocked := context.WithValue(context.Background(), tenantKey{}, "blocked")
+
+	if _, err := DecideWithRetry(blocked, engine, eventRequest(), fastRetryPolicy); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("DecideWithRetry() error = %v, want ErrUnauthorized from the context's tenant", err)
+	}
+}
+
+func TestNewEngineFromDatabaseWithRetryABIMismatch(t *testing.T) {
+	fakeABIContract(t, func(c *abiContract) { c.ABIVersion = abiVersion + 1 })
+	calls := 0
+	original := newEngineFromDatabase
+	newEngineFromDatabase = func(databaseURL string, opts ...EngineOption) (*DecisionEngine, error) {
+		calls++
+		return NewEngineFromDatabase(databaseURL, opts...)
+	}
+	t.Cleanup(func() { newEngineFromDatabase = original })
+
+	_, err := NewEngineFromDatabaseWithRetry(context.Background(), "postgres://localhost/corint", fastRetryPolicy)
+	if !errors.Is(err, ErrABIMismatch) {
+		t.Fatalf("error = %v, want ErrABIMismatch", err)
+	}
+	if calls != 1 {
+		t.Errorf("factory called %d times for an ABI mismatch, want 1", calls)
+	}
+}
@@ -0,0 +1,146 @@
+package corint
+
+/*
+#include <stdlib.h>
+
+char* corint_engine_rule_ast(void* engine, const char* rule_id);
+char* corint_engine_required_features(void* engine, const char* rule_id);
+int corint_engine_set_rule_enabled(void* engine, const char* rule_id, int enabled);
+void corint_string_free(char* s);
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"unsafe"
+)
+
+// ErrRuleNotFound is returned when the engine has no rule with the requested ID
+var ErrRuleNotFound = errors.New("rule not found")
+
+// errRuleLookupFailed is returned when a native per-rule lookup produces no result
+var errRuleLookupFailed = errors.New("rule lookup failed")
+
+// RuleAST returns the compiled program of a rule as JSON, for tooling that
+// visualizes how a rule was parsed. The JSON layout follows the native IR and
+// may change between native versions.
+func (e *DecisionEngine) RuleAST(ruleID string) (json.RawMessage, error) {
+	resultJSON, err := e.queryRule(ruleID, nativeRuleAST)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(resultJSON), nil
+}
+
+// RequiredFeatures returns the names of the request features a rule reads,
+// so callers can fetch exactly those before deciding
+func (e *DecisionEngine) RequiredFeatures(ruleID string) ([]string, error) {
+	resultJSON, err := e.queryRule(ruleID, nativeRequiredFeatures)
+	if err != nil {
+		return nil, err
+	}
+
+	var features []string
+	if err := json.Unmarshal(resultJSON, &features); err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// SetRuleEnabled enables or disables a rule for all subsequent decisions, as
+// an operational kill switch. Disabled rules are skipped when their ruleset
+// runs. Toggles survive Reload unless WithClearRuleTogglesOnReload is set.
+func (e *DecisionEngine) SetRuleEnabled(ruleID string, enabled bool) error {
+	if e.handle == nil {
+		return ErrEngineClosed
+	}
+
+	e.togglesMu.Lock()
+	defer e.togglesMu.Unlock()
+
+	status := nativeSetRuleEnabled(e.handle, ruleID, enabled)
+	if status == 0 && e.reloads > 0 {
+		e.reloadToggles[ruleID] = enabled
+	}
+
+	switch status {
+	case 0:
+		return nil
+	case -2:
+		return ErrRuleNotFound
+	default:
+		return errors.New("failed to set rule state")
+	}
+}
+
+// nativeSetRuleEnabled toggles a rule on a native engine handle and returns
+// the native status code; tests replace it to fake native toggles
+var nativeSetRuleEnabled = func(handle unsafe.Pointer, ruleID string, enabled bool) int {
+	cRuleID := C.CString(ruleID)
+	defer C.free(unsafe.Pointer(cRuleID))
+
+	var cEnabled C.int
+	if enabled {
+		cEnabled = 1
+	}
+	return int(C.corint_engine_set_rule_enabled(handle, cRuleID, cEnabled))
+}
+
+// queryRule runs a native per-rule lookup and converts its error envelope
+func (e *DecisionEngine) queryRule(ruleID string, query func(handle unsafe.Pointer, ruleID string) ([]byte, error)) ([]byte, error) {
+	if e.handle == nil {
+		return nil, ErrEngineClosed
+	}
+
+	resultJSON, err := query(e.handle, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	return ruleQueryResult(resultJSON)
+}
+
+// ruleQueryResult returns the result of a per-rule lookup, or the
+// DecisionError its native error envelope describes
+func ruleQueryResult(resultJSON []byte) ([]byte, error) {
+	var errorResp struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"error_code"`
+	}
+	if json.Unmarshal(resultJSON, &errorResp) == nil && errorResp.Error != "" {
+		return nil, &DecisionError{
+			Message:  errorResp.Error,
+			Code:     errorResp.ErrorCode,
+			sentinel: nativeErrorCodes[errorResp.ErrorCode],
+		}
+	}
+	return resultJSON, nil
+}
+
+// nativeRuleAST returns the compiled program JSON of a rule on a native engine
+// handle; tests replace it to fake native lookups
+var nativeRuleAST = func(handle unsafe.Pointer, ruleID string) ([]byte, error) {
+	return nativeRuleQuery(ruleID, func(cRuleID *C.char) *C.char {
+		return C.corint_engine_rule_ast(handle, cRuleID)
+	})
+}
+
+// nativeRequiredFeatures returns the required features JSON of a rule on a
+// native engine handle; tests replace it to fake native lookups
+var nativeRequiredFeatures = func(handle unsafe.Pointer, ruleID string) ([]byte, error) {
+	return nativeRuleQuery(ruleID, func(cRuleID *C.char) *C.char {
+		return C.corint_engine_required_features(handle, cRuleID)
+	})
+}
+
+// nativeRuleQuery calls a native per-rule lookup and copies and frees its result
+func nativeRuleQuery(ruleID string, call func(cRuleID *C.char) *C.char) ([]byte, error) {
+	cRuleID := C.CString(ruleID)
+	defer C.free(unsafe.Pointer(cRuleID))
+
+	resultPtr := call(cRuleID)
+	if resultPtr == nil {
+		return nil, errRuleLookupFailed
+	}
+	defer C.corint_string_free(resultPtr)
+	return []byte(C.GoString(resultPtr)), nil
+}
@@ -0,0 +1,74 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// ruleLookup fakes a native per-rule lookup answering known rules with their
+// JSON and others with the native rule_not_found error
+func ruleLookup(known map[string]string) func(unsafe.Pointer, string) ([]byte, error) {
+	return func(_ unsafe.Pointer, ruleID string) ([]byte, error) {
+		if result, ok := known[ruleID]; ok {
+			return []byte(result), nil
+		}
+		return []byte(fmt.Sprintf(`{"error":"rule not found: %s","success":false,"error_code":"rule_not_found"}`, ruleID)), nil
+	}
+}
+
+// fakeRuleLookups replaces the native rule AST and required features lookups for the rest of the test
+func fakeRuleLookups(t *testing.T, ast, features map[string]string) *DecisionEngine {
+	t.Helper()
+	originalAST, originalFeatures := nativeRuleAST, nativeRequiredFeatures
+	nativeRuleAST = ruleLookup(ast)
+	nativeRequiredFeatures = ruleLookup(features)
+	t.Cleanup(func() { nativeRuleAST, nativeRequiredFeatures = originalAST, originalFeatures })
+	return &DecisionEngine{handle: unsafe.Pointer(&testHandle), config: newEngineConfig(nil)}
+}
+
+func TestRuleAST(t *testing.T) {
+	program := `{"instructions":[{"LoadField":{"path":["event","amount"]}}]}`
+	engine := fakeRuleLookups(t, map[string]string{"high_amount": program}, nil)
+
+	ast, err := engine.RuleAST("high_amount")
+	if err != nil {
+		t.Fatalf("RuleAST: %v", err)
+	}
+	if string(ast) != program {
+		t.Errorf("RuleAST() = %s, want %s", ast, program)
+	}
+
+	_, err = engine.RuleAST("missing")
+	if !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("RuleAST(missing) error = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestRequiredFeatures(t *testing.T) {
+	engine := fakeRuleLookups(t, nil, map[string]string{"velocity": `["txn_count_24h","avg_amount"]`})
+
+	features, err := engine.RequiredFeatures("velocity")
+	if err != nil {
+		t.Fatalf("RequiredFeatures: %v", err)
+	}
+	if want := []string{"txn_count_24h", "avg_amount"}; !reflect.DeepEqual(features, want) {
+		t.Errorf("RequiredFeatures() = %v, want %v", features, want)
+	}
+
+	if _, err := engine.RequiredFeatures("missing"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("RequiredFeatures(missing) error = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestRuleLookupsOnClosedEngine(t *testing.T) {
+	closed := &DecisionEngine{}
+	if _, err := closed.RuleAST("high_amount"); !errors.Is(err, ErrEngineClosed) {
+		t.Errorf("RuleAST() error = %v, want ErrEngineClosed", err)
+	}
+	if _, err := closed.RequiredFeatures("high_amount"); !errors.Is(err, ErrEngineClosed) {
+		t.Errorf("RequiredFeatures() error = %v, want ErrEngineClosed", err)
+	}
+}
@@ -0,0 +1,93 @@
+package corint
+
+/*
+#include <stdlib.h>
+
+int corint_configure_runtime(const char* config_json);
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// minThreadStackSize is the smallest native worker stack size accepted by ConfigureRuntime
+const minThreadStackSize = 64 * 1024
+
+// ErrRuntimeConfigured is returned when the native runtime has already been
+// configured, or an engine was created before ConfigureRuntime was called
+var ErrRuntimeConfigured = errors.New("native runtime already configured")
+
+// RuntimeConfig configures the native runtime shared by all engines
+type RuntimeConfig struct {
+	// WorkerThreads is the number of native worker threads per engine; 0 uses the number of CPUs
+	WorkerThreads int `json:"worker_threads,omitempty"`
+	// ThreadStackSize is the stack size in bytes of each worker thread; 0 uses the native default
+	ThreadStackSize int `json:"thread_stack_size,omitempty"`
+	// LogBufferSize is the number of recent native log lines kept for RecentLogs; 0 uses the native default
+	LogBufferSize int `json:"log_buffer_size,omitempty"`
+}
+
+// validate checks that config values are within the ranges the native runtime accepts
+func (c RuntimeConfig) validate() error {
+	if c.WorkerThreads < 0 {
+		return fmt.Errorf("worker threads must not be negative, got %d", c.WorkerThreads)
+	}
+	if c.ThreadStackSize < 0 {
+		return fmt.Errorf("thread stack size must not be negative, got %d", c.ThreadStackSize)
+	}
+	if c.LogBufferSize < 0 {
+		return fmt.Errorf("log buffer size must not be negative, got %d", c.LogBufferSize)
+	}
+	if c.ThreadStackSize > 0 && c.ThreadStackSize < minThreadStackSize {
+		return fmt.Errorf("thread stack size must be at least %d bytes, got %d", minThreadStackSize, c.ThreadStackSize)
+	}
+	return nil
+}
+
+// nativeConfigureRuntime passes the runtime configuration JSON to the native
+// library and returns its status code; tests replace it to observe the call
+var nativeConfigureRuntime = func(configJSON []byte) int {
+	cConfig := C.CString(string(configJSON))
+	defer C.free(unsafe.Pointer(cConfig))
+	return int(C.corint_configure_runtime(cConfig))
+}
+
+var (
+	runtimeMu         sync.Mutex
+	runtimeConfigured bool
+)
+
+// ConfigureRuntime sets the native runtime configuration. It must be called
+// once, before InitLogging or any engine is created; later calls return
+// ErrRuntimeConfigured without reaching the native library.
+func ConfigureRuntime(config RuntimeConfig) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	runtimeMu.Lock()
+	defer runtimeMu.Unlock()
+	if runtimeConfigured {
+		return ErrRuntimeConfigured
+	}
+
+	switch nativeConfigureRuntime(configJSON) {
+	case 0:
+		runtimeConfigured = true
+		return nil
+	case -2:
+		runtimeConfigured = true
+		return ErrRuntimeConfigured
+	default:
+		return errors.New("native runtime rejected the configuration")
+	}
+}
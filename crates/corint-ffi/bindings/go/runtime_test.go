@@ -0,0 +1,97 @@
+package corint
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// fakeConfigureRuntime records native runtime configuration calls for the
+// rest of the test, answering each with status
+func fakeConfigureRuntime(t *testing.T, status int) *[]RuntimeConfig {
+	t.Helper()
+	original := nativeConfigureRuntime
+	var calls []RuntimeConfig
+	nativeConfigureRuntime = func(configJSON []byte) int {
+		var config RuntimeConfig
+		if err := json.Unmarshal(configJSON, &config); err != nil {
+			t.Errorf("native configuration is not valid JSON: %v", err)
+		}
+		calls = append(calls, config)
+		return status
+	}
+	t.Cleanup(func() {
+		nativeConfigureRuntime = original
+		runtimeConfigured = false
+	})
+	return &calls
+}
+
+func TestConfigureRuntimePassesValues(t *testing.T) {
+	calls := fakeConfigureRuntime(t, 0)
+
+	config := RuntimeConfig{WorkerThreads: 4, ThreadStackSize: 2 << 20, LogBufferSize: 128}
+	if err := ConfigureRuntime(config); err != nil {
+		t.Fatalf("ConfigureRuntime: %v", err)
+	}
+
+	if len(*calls) != 1 || (*calls)[0] != config {
+		t.Fatalf("native calls = %+v, want one call with %+v", *calls, config)
+	}
+}
+
+func TestConfigureRuntimeOnce(t *testing.T) {
+	calls := fakeConfigureRuntime(t, 0)
+
+	if err := ConfigureRuntime(RuntimeConfig{WorkerThreads: 2}); err != nil {
+		t.Fatalf("first ConfigureRuntime: %v", err)
+	}
+	if err := ConfigureRuntime(RuntimeConfig{WorkerThreads: 8}); !errors.Is(err, ErrRuntimeConfigured) {
+		t.Fatalf("second ConfigureRuntime = %v, want ErrRuntimeConfigured", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("native library configured %d times, want once", len(*calls))
+	}
+}
+
+func TestConfigureRuntimeAfterNativeStart(t *testing.T) {
+	fakeConfigureRuntime(t, -2)
+
+	if err := ConfigureRuntime(RuntimeConfig{}); !errors.Is(err, ErrRuntimeConfigured) {
+		t.Fatalf("ConfigureRuntime = %v, want ErrRuntimeConfigured", err)
+	}
+}
+
+func TestRuntimeConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  RuntimeConfig
+		wantErr bool
+	}{
+		{"defaults", RuntimeConfig{}, false},
+		{"all set", RuntimeConfig{WorkerThreads: 4, ThreadStackSize: minThreadStackSize, LogBufferSize: 10}, false},
+		{"negative workers", RuntimeConfig{WorkerThreads: -1}, true},
+		{"negative stack", RuntimeConfig{ThreadStackSize: -1}, true},
+		{"negative log buffer", RuntimeConfig{LogBufferSize: -1}, true},
+		{"stack too small", RuntimeConfig{ThreadStackSize: minThreadStackSize - 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigureRuntimeRejectsInvalidConfig(t *testing.T) {
+	calls := fakeConfigureRuntime(t, 0)
+
+	if err := ConfigureRuntime(RuntimeConfig{WorkerThreads: -3}); err == nil {
+		t.Fatal("expected a validation error")
+	}
+	if len(*calls) != 0 {
+		t.Fatal("invalid configuration reached the native library")
+	}
+}
@@ -0,0 +1,245 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidNumber is returned when request data contains NaN or infinite values
+var ErrInvalidNumber = errors.New("invalid number")
+
+// NumberPolicy controls how NaN and infinite values in request data are handled
+type NumberPolicy int
+
+const (
+	// RejectInvalidNumbers fails the decision with ErrInvalidNumber naming the field
+	RejectInvalidNumbers NumberPolicy = iota
+	// CoerceInvalidNumbersToNull sends NaN and infinite values as null
+	CoerceInvalidNumbersToNull
+)
+
+// sanitizeRequest applies the number policy to every data section of the
+// request. Sections containing invalid numbers are copied before coercion.
+func sanitizeRequest(request *DecisionRequest, policy NumberPolicy) error {
+	sections := []struct {
+		name string
+		data *map[string]interface{}
+	}{
+		{"event_data", &request.EventData},
+		{"features", &request.Features},
+		{"api", &request.API},
+		{"service", &request.Service},
+		{"llm", &request.LLM},
+		{"vars", &request.Vars},
+	}
+
+	for _, section := range sections {
+		if *section.data == nil {
+			continue
+		}
+		sanitized, changed, err := sanitizeValue(section.name, *section.data, policy)
+		if err != nil {
+			return err
+		}
+		if changed {
+			*section.data = sanitized.(map[string]interface{})
+		}
+	}
+	return nil
+}
+
+// sanitizeValue walks nested values looking for NaN and infinite values, with
+// fast paths for the types decoded JSON uses. It reports whether the returned
+// value differs from v.
+func sanitizeValue(path string, v interface{}, policy NumberPolicy) (interface{}, bool, error) {
+	switch value := v.(type) {
+	case float64:
+		return sanitizeFloat(path, value, policy)
+	case float32:
+		return sanitizeFloat(path, float64(value), policy)
+	case map[string]interface{}:
+		var copied map[string]interface{}
+		for key, item := range value {
+			sanitized, changed, err := sanitizeValue(path+"."+key, item, policy)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if copied == nil {
+					copied = make(map[string]interface{}, len(value))
+					for k, original := range value {
+						copied[k] = original
+					}
+				}
+				copied[key] = sanitized
+			}
+		}
+		if copied != nil {
+			return copied, true, nil
+		}
+		return value, false, nil
+	case []interface{}:
+		var copied []interface{}
+		for i, item := range value {
+			sanitized, changed, err := sanitizeValue(fmt.Sprintf("%s[%d]", path, i), item, policy)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if copied == nil {
+					copied = append([]interface{}(nil), value...)
+				}
+				copied[i] = sanitized
+			}
+		}
+		if copied != nil {
+			return copied, true, nil
+		}
+		return value, false, nil
+	case []float64:
+		var copied []interface{}
+		for i, item := range value {
+			sanitized, changed, err := sanitizeFloat(fmt.Sprintf("%s[%d]", path, i), item, policy)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if copied == nil {
+					copied = make([]interface{}, len(value))
+					for j, original := range value {
+						copied[j] = original
+					}
+				}
+				copied[i] = sanitized
+			}
+		}
+		if copied != nil {
+			return copied, true, nil
+		}
+		return value, false, nil
+	default:
+		return sanitizeReflect(path, v, policy)
+	}
+}
+
+// sanitizeReflect handles the containers sanitizeValue has no fast path for,
+// such as typed maps and slices, pointers and structs. Containers holding
+// invalid numbers are coerced into their generic JSON form: maps and structs
+// become map[string]interface{} keyed like encoding/json, slices and arrays
+// become []interface{}.
+func sanitizeReflect(path string, v interface{}, policy NumberPolicy) (interface{}, bool, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return sanitizeFloat(path, rv.Float(), policy)
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return v, false, nil
+		}
+		sanitized, changed, err := sanitizeValue(path, rv.Elem().Interface(), policy)
+		if err != nil || !changed {
+			return v, false, err
+		}
+		return sanitized, true, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return v, false, nil
+		}
+		var (
+			entries = make(map[string]interface{}, rv.Len())
+			changed bool
+		)
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			sanitized, itemChanged, err := sanitizeValue(path+"."+key, iter.Value().Interface(), policy)
+			if err != nil {
+				return nil, false, err
+			}
+			entries[key] = sanitized
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v, false, nil
+		}
+		return entries, true, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return v, false, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return v, false, nil
+		}
+		var (
+			items   = make([]interface{}, rv.Len())
+			changed bool
+		)
+		for i := range items {
+			sanitized, itemChanged, err := sanitizeValue(fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface(), policy)
+			if err != nil {
+				return nil, false, err
+			}
+			items[i] = sanitized
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v, false, nil
+		}
+		return items, true, nil
+	case reflect.Struct:
+		var (
+			fields  = make(map[string]interface{})
+			changed bool
+		)
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			name, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			sanitized, fieldChanged, err := sanitizeValue(path+"."+name, rv.Field(i).Interface(), policy)
+			if err != nil {
+				return nil, false, err
+			}
+			fields[name] = sanitized
+			changed = changed || fieldChanged
+		}
+		if !changed {
+			return v, false, nil
+		}
+		return fields, true, nil
+	default:
+		return v, false, nil
+	}
+}
+
+// jsonFieldName returns the name encoding/json uses for a struct field, or
+// false when the field is not encoded
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// sanitizeFloat applies the number policy to a single float
+func sanitizeFloat(path string, f float64, policy NumberPolicy) (interface{}, bool, error) {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f, false, nil
+	}
+	if policy == CoerceInvalidNumbersToNull {
+		return nil, true, nil
+	}
+	return nil, false, fmt.Errorf("%w at %s: %v", ErrInvalidNumber, path, f)
+}
@@ -0,0 +1,88 @@
+package corint
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+type sanitizeScore struct {
+	Value    float64 `json:"value"`
+	Weight   float64 `json:"-"`
+	internal float64
+}
+
+func TestSanitizeRejectsNestedInvalidNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		path  string
+	}{
+		{"nested map", map[string]interface{}{"risk": map[string]interface{}{"score": math.NaN()}}, "event_data.field.risk.score"},
+		{"typed map", map[string]float64{"score": math.Inf(1)}, "event_data.field.score"},
+		{"slice of maps", []map[string]interface{}{{"ok": 1.0}, {"score": math.NaN()}}, "event_data.field[1].score"},
+		{"struct", sanitizeScore{Value: math.NaN()}, "event_data.field.value"},
+		{"pointer to struct", &sanitizeScore{Value: math.Inf(-1)}, "event_data.field.value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &DecisionRequest{EventData: map[string]interface{}{"field": tt.value}}
+			err := sanitizeRequest(request, RejectInvalidNumbers)
+			if !errors.Is(err, ErrInvalidNumber) {
+				t.Fatalf("sanitizeRequest() error = %v, want ErrInvalidNumber", err)
+			}
+			if !strings.Contains(err.Error(), tt.path) {
+				t.Errorf("error %q does not name %s", err, tt.path)
+			}
+		})
+	}
+}
+
+func TestSanitizeCoercesNestedInvalidNumbers(t *testing.T) {
+	request := &DecisionRequest{EventData: map[string]interface{}{
+		"typed":  map[string]float64{"score": math.NaN(), "amount": 12.5},
+		"list":   []map[string]interface{}{{"score": math.Inf(1)}},
+		"struct": sanitizeScore{Value: math.NaN(), Weight: math.NaN(), internal: math.NaN()},
+	}}
+	original := request.EventData["typed"].(map[string]float64)
+
+	if err := sanitizeRequest(request, CoerceInvalidNumbersToNull); err != nil {
+		t.Fatalf("sanitizeRequest: %v", err)
+	}
+
+	typed := request.EventData["typed"].(map[string]interface{})
+	if typed["score"] != nil || typed["amount"] != 12.5 {
+		t.Errorf("typed map = %v, want score null and amount kept", typed)
+	}
+	if !math.IsNaN(original["score"]) {
+		t.Error("caller's map was modified")
+	}
+
+	list := request.EventData["list"].([]interface{})
+	if entry := list[0].(map[string]interface{}); entry["score"] != nil {
+		t.Errorf("list[0] = %v, want score null", entry)
+	}
+
+	fields := request.EventData["struct"].(map[string]interface{})
+	if len(fields) != 1 || fields["value"] != nil {
+		t.Errorf("struct = %v, want only value set to null", fields)
+	}
+}
+
+func TestSanitizeLeavesValidValuesUntouched(t *testing.T) {
+	typed := map[string]float64{"score": 1}
+	data := []byte("raw")
+	request := &DecisionRequest{EventData: map[string]interface{}{"typed": typed, "bytes": data}}
+
+	if err := sanitizeRequest(request, RejectInvalidNumbers); err != nil {
+		t.Fatalf("sanitizeRequest: %v", err)
+	}
+	if _, ok := request.EventData["typed"].(map[string]float64); !ok {
+		t.Error("valid typed map was converted")
+	}
+	if _, ok := request.EventData["bytes"].([]byte); !ok {
+		t.Error("byte slice was converted")
+	}
+}
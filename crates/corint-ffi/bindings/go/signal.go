@@ -0,0 +1,59 @@
+package corint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// LegacyDecisionCodes maps numeric decision codes emitted by older native
+// builds to signal types. It may be extended before decoding responses.
+var LegacyDecisionCodes = map[int]string{
+	0: "approve",
+	1: "decline",
+	2: "review",
+}
+
+// UnmarshalJSON decodes a signal object, accepting numeric legacy codes for the
+// type as well as a bare string or numeric code in place of the object
+func (s *DecisionSignal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var raw struct {
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		if raw.Type == nil {
+			s.Type = ""
+			return nil
+		}
+		data = raw.Type
+	}
+
+	signalType, err := decodeSignalType(data)
+	if err != nil {
+		return err
+	}
+	s.Type = signalType
+	return nil
+}
+
+// decodeSignalType decodes a signal type given as a string or a legacy numeric code
+func decodeSignalType(data []byte) (string, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return name, nil
+	}
+
+	var code int
+	if err := json.Unmarshal(data, &code); err != nil {
+		return "", fmt.Errorf("invalid decision signal type: %s", data)
+	}
+	name, ok := LegacyDecisionCodes[code]
+	if !ok {
+		return "", fmt.Errorf("unknown legacy decision code %d", code)
+	}
+	return name, nil
+}
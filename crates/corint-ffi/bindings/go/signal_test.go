@@ -0,0 +1,67 @@
+package corint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecisionSignalUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"string type", `{"type":"review"}`, "review"},
+		{"numeric type", `{"type":1}`, "decline"},
+		{"bare string", `"approve"`, "approve"},
+		{"bare code", `2`, "review"},
+		{"bare zero code", `0`, "approve"},
+		{"missing type", `{}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var signal DecisionSignal
+			if err := json.Unmarshal([]byte(tt.json), &signal); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+			}
+			if signal.Type != tt.want {
+				t.Errorf("Unmarshal(%s).Type = %q, want %q", tt.json, signal.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecisionSignalUnmarshalInvalid(t *testing.T) {
+	for _, data := range []string{`{"type":7}`, `9`, `{"type":true}`, `[1]`} {
+		var signal DecisionSignal
+		if err := json.Unmarshal([]byte(data), &signal); err == nil {
+			t.Errorf("Unmarshal(%s) = %q, want an error", data, signal.Type)
+		}
+	}
+}
+
+func TestLegacyDecisionCodesExtensible(t *testing.T) {
+	LegacyDecisionCodes[9] = "challenge"
+	defer delete(LegacyDecisionCodes, 9)
+
+	var signal DecisionSignal
+	if err := json.Unmarshal([]byte(`{"type":9}`), &signal); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if signal.Type != "challenge" {
+		t.Errorf("Type = %q, want challenge", signal.Type)
+	}
+}
+
+func TestDecideDecodesLegacySignal(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(`{"request_id":"req_1","result":{"signal":1,"actions":[],"score":90,"triggered_rules":[]}}`))
+
+	response, err := engine.Decide(eventRequest())
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if response.Decision != "decline" {
+		t.Errorf("Decision = %q, want decline", response.Decision)
+	}
+}
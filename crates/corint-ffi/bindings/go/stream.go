@@ -0,0 +1,76 @@
+package corint
+
+import (
+	"context"
+	"time"
+)
+
+// DecideResult is the outcome of a single decision produced by a stream helper
+type DecideResult struct {
+	Request  *DecisionRequest
+	Response *DecisionResponse
+	Err      error
+}
+
+// DecideStreamLimited consumes requests from in at no more than rps requests
+// per second and emits one result per request, in order, on the returned
+// channel. The output channel is closed when in is closed or ctx is done.
+// Engines implementing ContextEngine decide with ctx.
+// A non-positive rps disables rate limiting, as does an rps too high for the
+// interval between requests to be at least a nanosecond.
+func DecideStreamLimited(ctx context.Context, e Engine, in <-chan *DecisionRequest, rps float64) <-chan DecideResult {
+	out := make(chan DecideResult)
+
+	go func() {
+		defer close(out)
+
+		var tick <-chan time.Time
+		if interval := streamInterval(rps); interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		first := true
+		for {
+			var request *DecisionRequest
+			select {
+			case <-ctx.Done():
+				return
+			case r, ok := <-in:
+				if !ok {
+					return
+				}
+				request = r
+			}
+
+			// The first request is processed immediately; later ones wait for the limiter
+			if tick != nil && !first {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tick:
+				}
+			}
+			first = false
+
+			response, err := decideContext(ctx, e, request)
+			select {
+			case out <- DecideResult{Request: request, Response: response, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// streamInterval returns the time between requests at rps, or zero when the
+// stream is unlimited
+func streamInterval(rps float64) time.Duration {
+	if !(rps > 0) {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / rps)
+}
@@ -0,0 +1,66 @@
+package corint
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// feed returns a closed channel holding n indexed requests
+func feed(n int) <-chan *DecisionRequest {
+	in := make(chan *DecisionRequest, n)
+	for _, request := range indexedRequests(n) {
+		in <- request
+	}
+	close(in)
+	return in
+}
+
+func TestDecideStreamLimitedRate(t *testing.T) {
+	const n = 5
+	start := time.Now()
+	var results int
+	for result := range DecideStreamLimited(context.Background(), answering("approve"), feed(n), 100) {
+		if result.Err != nil {
+			t.Fatalf("result error: %v", result.Err)
+		}
+		results++
+	}
+	if results != n {
+		t.Fatalf("got %d results, want %d", results, n)
+	}
+	// The first request is immediate and each later one waits 10ms
+	if elapsed := time.Since(start); elapsed < (n-1)*10*time.Millisecond {
+		t.Errorf("stream finished in %v, faster than 100 rps allows", elapsed)
+	}
+}
+
+func TestDecideStreamLimitedUnlimitedRates(t *testing.T) {
+	for _, rps := range []float64{0, -1, 2e9, math.Inf(1), math.NaN()} {
+		var results int
+		for range DecideStreamLimited(context.Background(), answering("approve"), feed(3), rps) {
+			results++
+		}
+		if results != 3 {
+			t.Errorf("rps %v: got %d results, want 3", rps, results)
+		}
+	}
+}
+
+func TestDecideStreamLimitedPassesContext(t *testing.T) {
+	engine := newFakeEngine(t, respondWith(approveResponse), WithAuthorizer(tenantAuthorizer))
+	blocked := context.WithValue(context.Background(), tenantKey{}, "blocked")
+
+	results := 0
+	for result := range DecideStreamLimited(blocked, engine, feed(2), 0) {
+		results++
+		if !errors.Is(result.Err, ErrUnauthorized) {
+			t.Errorf("result error = %v, want ErrUnauthorized from the context's tenant", result.Err)
+		}
+	}
+	if results != 2 {
+		t.Errorf("stream emitted %d results, want 2", results)
+	}
+}
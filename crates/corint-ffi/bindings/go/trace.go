@@ -0,0 +1,158 @@
+package corint
+
+import "encoding/json"
+
+// Trace is the native execution trace of a decision
+type Trace map[string]interface{}
+
+// Truncated reports whether the native engine truncated the trace to fit the
+// configured maximum trace size
+func (t Trace) Truncated() bool {
+	truncated, _ := t["truncated"].(bool)
+	return truncated
+}
+
+// Clone returns a deep copy of the trace, so the copy can be modified
+// without affecting t
+func (t Trace) Clone() Trace {
+	if t == nil {
+		return nil
+	}
+	return Trace(cloneJSONValue(map[string]interface{}(t)).(map[string]interface{}))
+}
+
+// cloneJSONValue deep-copies the maps and slices of a decoded JSON value
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = cloneJSONValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = cloneJSONValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// tracePipeline mirrors the pipeline section of the native trace
+type tracePipeline struct {
+	PipelineID      string            `json:"pipeline_id"`
+	Rulesets        []traceRuleset    `json:"rulesets"`
+	FinalConclusion []traceConclusion `json:"final_conclusion"`
+}
+
+// traceRuleset mirrors a ruleset entry of the native trace
+type traceRuleset struct {
+	RulesetID  string            `json:"ruleset_id"`
+	Rules      []traceRule       `json:"rules"`
+	Conclusion []traceConclusion `json:"conclusion"`
+}
+
+// traceRule mirrors a rule entry of the native trace
+type traceRule struct {
+	RuleID     string          `json:"rule_id"`
+	RuleName   string          `json:"rule_name"`
+	Triggered  bool            `json:"triggered"`
+	Score      *int            `json:"score"`
+	Conditions []ConditionEval `json:"conditions"`
+}
+
+// ConditionEval is a single condition evaluation recorded in the trace
+type ConditionEval struct {
+	// Expression is the condition source, e.g. "event.transaction.amount > 10000"
+	Expression string `json:"expression"`
+	// LeftValue is the evaluated left-hand operand, if recorded
+	LeftValue interface{} `json:"left_value,omitempty"`
+	// Operator is the comparison operator, e.g. ">", "==", "in"
+	Operator string `json:"operator,omitempty"`
+	// RightValue is the evaluated right-hand operand, if recorded
+	RightValue interface{} `json:"right_value,omitempty"`
+	// Result is the outcome of the condition
+	Result bool `json:"result"`
+	// Nested holds the member conditions when this is an any/all group
+	Nested []ConditionEval `json:"nested,omitempty"`
+	// GroupType is "any" or "all" for logical groups
+	GroupType string `json:"group_type,omitempty"`
+}
+
+// traceConclusion mirrors a conclusion entry of the native trace
+type traceConclusion struct {
+	Condition  string `json:"condition"`
+	Matched    bool   `json:"matched"`
+	Signal     string `json:"signal"`
+	Reason     string `json:"reason"`
+	TotalScore *int   `json:"total_score"`
+}
+
+// RuleEval is the trace of a single rule evaluation
+type RuleEval struct {
+	RulesetID  string
+	RuleID     string
+	RuleName   string
+	Triggered  bool
+	Score      int
+	Conditions []ConditionEval
+}
+
+// RuleEvals returns every rule evaluation recorded in the trace, in ruleset
+// order. Conditions is empty for traces recorded without condition detail.
+func (t Trace) RuleEvals() []RuleEval {
+	pipeline := t.pipeline()
+	if pipeline == nil {
+		return nil
+	}
+
+	var evals []RuleEval
+	for _, ruleset := range pipeline.Rulesets {
+		for _, rule := range ruleset.Rules {
+			eval := RuleEval{
+				RulesetID:  ruleset.RulesetID,
+				RuleID:     rule.RuleID,
+				RuleName:   rule.RuleName,
+				Triggered:  rule.Triggered,
+				Conditions: rule.Conditions,
+			}
+			if rule.Score != nil {
+				eval.Score = *rule.Score
+			}
+			evals = append(evals, eval)
+		}
+	}
+	return evals
+}
+
+// Leaves returns the leaf comparisons of the condition, descending into any/all groups
+func (c ConditionEval) Leaves() []ConditionEval {
+	if len(c.Nested) == 0 {
+		return []ConditionEval{c}
+	}
+	var leaves []ConditionEval
+	for _, nested := range c.Nested {
+		leaves = append(leaves, nested.Leaves()...)
+	}
+	return leaves
+}
+
+// pipeline decodes the pipeline section of the trace, returning nil when absent or malformed
+func (t Trace) pipeline() *tracePipeline {
+	raw, ok := t["pipeline"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var pipeline tracePipeline
+	if err := json.Unmarshal(data, &pipeline); err != nil {
+		return nil
+	}
+	return &pipeline
+}
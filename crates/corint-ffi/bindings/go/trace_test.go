@@ -0,0 +1,116 @@
+package corint
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTraceTruncated(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace Trace
+		want  bool
+	}{
+		{"nil", nil, false},
+		{"untruncated", Trace{"pipeline": map[string]interface{}{}}, false},
+		{"truncated", Trace{"truncated": true, "original_bytes": 1 << 20}, true},
+		{"malformed flag", Trace{"truncated": "yes"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.trace.Truncated(); got != tt.want {
+			t.Errorf("%s: Truncated() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecideMaxTraceBytes(t *testing.T) {
+	largeTrace := map[string]interface{}{
+		"pipeline": map[string]interface{}{
+			"pipeline_id": "login",
+			"rulesets": []interface{}{map[string]interface{}{
+				"ruleset_id": "login_risk",
+				"rules": []interface{}{map[string]interface{}{
+					"rule_id":    "geo",
+					"conditions": []interface{}{map[string]interface{}{"expression": strings.Repeat("x", 4096)}},
+				}},
+			}},
+		},
+	}
+
+	var sentLimit int
+	engine := newFakeEngine(t, func(requestJSON []byte) ([]byte, error) {
+		var request DecisionRequest
+		if err := json.Unmarshal(requestJSON, &request); err != nil {
+			return nil, err
+		}
+		sentLimit = request.Options.MaxTraceBytes
+
+		trace := Trace(largeTrace)
+		if data, _ := json.Marshal(trace); sentLimit > 0 && len(data) > sentLimit {
+			trace = Trace{"truncated": true, "original_bytes": len(data)}
+		}
+		response := map[string]interface{}{
+			"request_id": "req_1",
+			"result":     map[string]interface{}{"signal": map[string]interface{}{"type": "approve"}},
+			"trace":      trace,
+		}
+		return json.Marshal(response)
+	}, WithMaxTraceBytes(1024))
+
+	request := eventRequest()
+	request.Options.EnableTrace = true
+	response, err := engine.Decide(request)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if sentLimit != 1024 {
+		t.Errorf("max_trace_bytes sent natively = %d, want 1024", sentLimit)
+	}
+	if !response.Trace.Truncated() {
+		t.Errorf("Trace = %v, want a truncated trace", response.Trace)
+	}
+
+	request.Options.MaxTraceBytes = 1 << 20
+	response, err = engine.Decide(request)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if sentLimit != 1<<20 {
+		t.Errorf("request limit was overridden: sent %d", sentLimit)
+	}
+	if response.Trace.Truncated() {
+		t.Error("trace within the request limit was truncated")
+	}
+}
+
+func TestRuleEvalsConditions(t *testing.T) {
+	evals := fixtureResponse(t).Trace.RuleEvals()
+	if len(evals) != 3 {
+		t.Fatalf("RuleEvals() returned %d rules, want 3", len(evals))
+	}
+
+	velocity := evals[2]
+	if velocity.RulesetID != "login_risk" || velocity.RuleID != "velocity" || velocity.Triggered || velocity.Score != 30 {
+		t.Errorf("velocity = %+v", velocity)
+	}
+	want := ConditionEval{
+		Expression: "features.txn_count_24h >= 10",
+		LeftValue:  float64(3),
+		Operator:   ">=",
+		RightValue: float64(10),
+	}
+	if len(velocity.Conditions) != 1 || !reflect.DeepEqual(velocity.Conditions[0], want) {
+		t.Errorf("velocity conditions = %+v, want %+v", velocity.Conditions, want)
+	}
+
+	group := evals[1].Conditions
+	if len(group) != 1 || group[0].GroupType != "all" || !group[0].Result {
+		t.Fatalf("high_amount conditions = %+v, want one all group", group)
+	}
+	leaves := group[0].Leaves()
+	if len(leaves) != 2 || leaves[0].Expression != "event.amount > 500" || leaves[1].LeftValue != "DE" {
+		t.Errorf("high_amount leaves = %+v", leaves)
+	}
+}
@@ -0,0 +1,24 @@
+package corint
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidOptions is returned when DecisionOptions contain contradictory settings
+var ErrInvalidOptions = errors.New("invalid decision options")
+
+// ErrEmptyEventData is returned when a request has no event data, unless
+// WithEmptyEventDecision configures a default decision
+var ErrEmptyEventData = errors.New("decision request has no event data")
+
+// Validate reports contradictory or out-of-range option combinations
+func (o DecisionOptions) Validate() error {
+	if o.MaxTraceBytes < 0 {
+		return fmt.Errorf("%w: max_trace_bytes must not be negative, got %d", ErrInvalidOptions, o.MaxTraceBytes)
+	}
+	if o.MaxTraceBytes > 0 && !o.EnableTrace {
+		return fmt.Errorf("%w: max_trace_bytes requires enable_trace", ErrInvalidOptions)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package corint
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecisionOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options DecisionOptions
+		valid   bool
+	}{
+		{"zero", DecisionOptions{}, true},
+		{"trace", DecisionOptions{EnableTrace: true}, true},
+		{"capped trace", DecisionOptions{EnableTrace: true, MaxTraceBytes: 4096}, true},
+		{"first action only", DecisionOptions{FirstActionOnly: true}, true},
+		{"negative cap", DecisionOptions{EnableTrace: true, MaxTraceBytes: -1}, false},
+		{"cap without trace", DecisionOptions{MaxTraceBytes: 4096}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidOptions) {
+				t.Errorf("Validate() = %v, want ErrInvalidOptions", err)
+			}
+		})
+	}
+}
+
+func TestDecideRejectsInvalidOptions(t *testing.T) {
+	called := false
+	engine := newFakeEngine(t, func([]byte) ([]byte, error) {
+		called = true
+		return []byte(approveResponse), nil
+	})
+
+	request := eventRequest()
+	request.Options.MaxTraceBytes = 1024
+	if _, err := engine.Decide(request); !errors.Is(err, ErrInvalidOptions) {
+		t.Fatalf("Decide() error = %v, want ErrInvalidOptions", err)
+	}
+	if called {
+		t.Error("invalid options reached the native engine")
+	}
+}
@@ -0,0 +1,60 @@
+package corint
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestVersionConcurrentFreesNativeStrings(t *testing.T) {
+	before := nativeLiveStrings()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if Version() == "" {
+					t.Error("Version() returned an empty string")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if after := nativeLiveStrings(); after != before {
+		t.Errorf("native live strings = %d after Version calls, want %d", after, before)
+	}
+}
+
+// fakeVersionInfo makes native version info calls return info for the rest of the test
+func fakeVersionInfo(t *testing.T, info string) {
+	t.Helper()
+	original := nativeVersionInfo
+	nativeVersionInfo = func() ([]byte, error) {
+		return []byte(info), nil
+	}
+	t.Cleanup(func() { nativeVersionInfo = original })
+}
+
+func TestVersionInfo(t *testing.T) {
+	fakeVersionInfo(t, `{"version":"0.3.1","git_commit":"4f2a9c1","build_features":["sqlx","redis"],"abi_version":1}`)
+
+	info, err := VersionInfo()
+	if err != nil {
+		t.Fatalf("VersionInfo: %v", err)
+	}
+	want := BuildInfo{Version: "0.3.1", GitCommit: "4f2a9c1", BuildFeatures: []string{"sqlx", "redis"}, ABIVersion: 1}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("VersionInfo() = %+v, want %+v", info, want)
+	}
+}
+
+func TestVersionInfoMalformed(t *testing.T) {
+	fakeVersionInfo(t, `{"version":`)
+	if _, err := VersionInfo(); err == nil {
+		t.Fatal("expected an error for malformed version info")
+	}
+}
@@ -9,6 +9,8 @@
 #ifndef CORINT_FFI_H
 #define CORINT_FFI_H
 
+#include <stddef.h>
+
 #ifdef __cplusplus
 extern "C" {
 #endif
@@ -19,10 +21,29 @@ extern "C" {
 typedef void* CorintEngine;
 
 /**
- * Initialize the logging system
+ * Initialize the logging system (safe to call more than once)
  */
 void corint_init_logging(void);
 
+/**
+ * Get the most recent native log lines
+ *
+ * @param n Maximum number of lines to return
+ * @return JSON array of log lines, oldest first, or NULL on failure
+ *         The returned string must be freed with corint_string_free()
+ */
+char* corint_recent_logs(size_t n);
+
+/**
+ * Configure the native runtime used by engines created afterwards
+ *
+ * @param config_json JSON object with optional "worker_threads",
+ *                    "thread_stack_size" and "log_buffer_size" fields
+ * @return 0 on success, -1 if the configuration is invalid, -2 if the runtime
+ *         was already configured or an engine was already created
+ */
+int corint_configure_runtime(const char* config_json);
+
 /**
  * Create a new decision engine from a file system repository
  *
@@ -39,6 +60,28 @@ CorintEngine corint_engine_new(const char* repository_path);
  */
 CorintEngine corint_engine_new_from_database(const char* database_url);
 
+/**
+ * Create a new decision engine serving several named file system repositories
+ *
+ * @param repositories_json JSON object mapping repository names to paths
+ * @return Engine handle, or NULL on failure
+ */
+CorintEngine corint_engine_new_multi(const char* repositories_json);
+
+/**
+ * Execute a decision against a named repository
+ *
+ * @param engine Engine handle created by corint_engine_new_multi
+ * @param repository Repository name
+ * @param request_json JSON-encoded decision request
+ * @return JSON-encoded decision response, or NULL on failure. Unknown
+ *         repositories yield an error response with
+ *         "error_code": "unknown_repository".
+ *         The returned string must be freed with corint_string_free()
+ */
+char* corint_engine_decide_in_repository(CorintEngine engine, const char* repository,
+                                         const char* request_json);
+
 /**
  * Execute a decision using the engine
  *
@@ -49,6 +92,109 @@ CorintEngine corint_engine_new_from_database(const char* database_url);
  */
 char* corint_engine_decide(CorintEngine engine, const char* request_json);
 
+/**
+ * Opaque handle to a decision response read in chunks
+ */
+typedef void* CorintResponseReader;
+
+/**
+ * Execute a decision and return the response as a chunk reader
+ *
+ * The reader owns the serialized response, so callers can decode it from
+ * bounded pieces without copying it whole. Available when corint_version_info
+ * lists the "chunked_responses" capability.
+ *
+ * @param engine Engine handle
+ * @param request_json JSON-encoded decision request
+ * @return Reader over the JSON-encoded decision response, or NULL on failure
+ *         The returned reader must be freed with corint_response_reader_free()
+ */
+CorintResponseReader corint_engine_decide_chunked(CorintEngine engine, const char* request_json);
+
+/**
+ * Get the total size in bytes of a chunked response
+ *
+ * @param reader Response reader
+ * @return Response size in bytes
+ */
+size_t corint_response_len(CorintResponseReader reader);
+
+/**
+ * Copy the next chunk of a response into buf
+ *
+ * @param reader Response reader
+ * @param buf Destination buffer
+ * @param buf_len Size of buf in bytes
+ * @return Number of bytes written, 0 once the response is exhausted, -1 on invalid arguments
+ */
+ptrdiff_t corint_read_chunk(CorintResponseReader reader, unsigned char* buf, size_t buf_len);
+
+/**
+ * Free a chunk reader
+ *
+ * @param reader Response reader to free
+ */
+void corint_response_reader_free(CorintResponseReader reader);
+
+/**
+ * Reload the engine's repository without interrupting decisions
+ *
+ * In-flight decisions finish on the previous state, which is freed once they
+ * complete.
+ *
+ * @param engine Engine handle
+ * @return 0 on success, -1 if reloading failed (the current state is kept),
+ *         -2 if the engine cannot be reloaded (multi-repository engines)
+ */
+int corint_engine_reload(CorintEngine engine);
+
+/**
+ * Reload the engine's repository, optionally clearing rule toggles
+ *
+ * Behaves like corint_engine_reload. Rules disabled with
+ * corint_engine_set_rule_enabled stay disabled unless clear_rule_toggles is
+ * non-zero, in which case every rule of the new state starts enabled.
+ *
+ * @param engine Engine handle
+ * @param clear_rule_toggles Non-zero to drop rule toggles instead of keeping them
+ * @return 0 on success, -1 if reloading failed (the current state is kept),
+ *         -2 if the engine cannot be reloaded (multi-repository engines)
+ */
+int corint_engine_reload_with_options(CorintEngine engine, int clear_rule_toggles);
+
+/**
+ * Enable or disable a rule for all subsequent decisions
+ *
+ * The toggle is kept across corint_engine_reload for rules that still exist,
+ * unless the reload clears rule toggles.
+ *
+ * @param engine Engine handle
+ * @param rule_id Rule identifier
+ * @param enabled Non-zero to enable the rule, zero to disable it
+ * @return 0 on success, -1 on invalid arguments, -2 if the rule does not exist
+ */
+int corint_engine_set_rule_enabled(CorintEngine engine, const char* rule_id, int enabled);
+
+/**
+ * Get the compiled program of a rule as JSON
+ *
+ * @param engine Engine handle
+ * @param rule_id Rule identifier
+ * @return JSON string with the compiled program, or an error response with
+ *         "error_code": "rule_not_found" for unknown rules (must be freed with corint_string_free)
+ */
+char* corint_engine_rule_ast(CorintEngine engine, const char* rule_id);
+
+/**
+ * Get the names of the request features a rule reads
+ *
+ * @param engine Engine handle
+ * @param rule_id Rule identifier
+ * @return JSON array of feature names, or an error response with
+ *         "error_code": "rule_not_found" for unknown rules (must be freed with corint_string_free)
+ */
+char* corint_engine_required_features(CorintEngine engine, const char* rule_id);
+
 /**
  * Free a decision engine
  *
@@ -63,6 +209,14 @@ void corint_engine_free(CorintEngine engine);
  */
 void corint_string_free(char* s);
 
+/**
+ * Get the number of strings returned by CORINT FFI functions that have not
+ * been freed with corint_string_free(), for leak checks in binding tests
+ *
+ * @return Number of live strings
+ */
+size_t corint_live_strings(void);
+
 /**
  * Get the CORINT version
  *
@@ -70,6 +224,22 @@ void corint_string_free(char* s);
  */
 char* corint_version(void);
 
+/**
+ * Get build details of the CORINT library
+ *
+ * @return JSON object with "version", "git_commit", "build_features",
+ *         "abi_version" and "capabilities" (must be freed with corint_string_free())
+ */
+char* corint_version_info(void);
+
+/**
+ * Report the ABI contract of the CORINT library
+ *
+ * @return JSON object with "abi_version", "pointer_size" and "c_int_size"
+ *         (must be freed with corint_string_free())
+ */
+char* corint_abi_check(void);
+
 #ifdef __cplusplus
 }
 #endif
@@ -3,23 +3,122 @@
 //! Foreign Function Interface for calling CORINT from other languages.
 //! This crate provides C-compatible bindings for Python, Go, TypeScript, and Java.
 
-use std::ffi::{CStr, CString};
-use std::os::raw::c_char;
+use std::collections::{BTreeMap, HashMap};
+use std::ffi::CStr;
+use std::os::raw::{c_char, c_int};
 use std::ptr;
-use std::sync::Arc;
+use std::sync::{Arc, OnceLock, RwLock};
 
-use corint_sdk::{DecisionEngineBuilder, DecisionRequest, RepositoryConfig};
+use corint_sdk::{
+    DecisionEngine, DecisionEngineBuilder, DecisionRequest, RepositoryConfig, SdkError,
+};
+use tokio::runtime::Runtime;
 
+mod logging;
 mod types;
 mod utils;
 
 pub use types::*;
 pub use utils::*;
 
+/// Runtime settings, fixed by corint_configure_runtime or the first engine creation
+static RUNTIME_SETTINGS: OnceLock<RuntimeSettings> = OnceLock::new();
+
+/// Build a tokio runtime using the configured runtime settings
+fn build_runtime() -> std::io::Result<Runtime> {
+    let settings = RUNTIME_SETTINGS.get_or_init(RuntimeSettings::default);
+
+    let mut builder = tokio::runtime::Builder::new_multi_thread();
+    builder.enable_all();
+    if let Some(worker_threads) = settings.worker_threads {
+        builder.worker_threads(worker_threads);
+    }
+    if let Some(thread_stack_size) = settings.thread_stack_size {
+        builder.thread_stack_size(thread_stack_size);
+    }
+    builder.build()
+}
+
+/// Log buffer capacity from the configured runtime settings
+fn log_buffer_size() -> usize {
+    RUNTIME_SETTINGS
+        .get_or_init(RuntimeSettings::default)
+        .log_buffer_size
+        .unwrap_or(logging::DEFAULT_LOG_BUFFER_SIZE)
+}
+
 /// Initialize the logging system
+///
+/// Safe to call more than once. Engines also initialize logging on creation
+/// so that recent log lines are always available through corint_recent_logs.
 #[no_mangle]
 pub extern "C" fn corint_init_logging() {
-    env_logger::init();
+    logging::install_logger(log_buffer_size());
+}
+
+/// Get the most recent native log lines as a JSON array of strings
+///
+/// The buffer is shared by all engines in the process and ordered oldest first.
+///
+/// # Safety
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub extern "C" fn corint_recent_logs(n: usize) -> *mut c_char {
+    let lines = logging::log_buffer(log_buffer_size()).recent(n);
+    match serde_json::to_string(&lines) {
+        Ok(s) => to_c_string(&s),
+        Err(_) => ptr::null_mut(),
+    }
+}
+
+/// Configure the native runtime used by engines created afterwards
+///
+/// Accepts a JSON object with optional `worker_threads`, `thread_stack_size`
+/// and `log_buffer_size` fields. Returns 0 on success, -1 if the configuration is invalid and -2 if
+/// the runtime was already configured or an engine was already created.
+///
+/// # Safety
+/// - config_json must be a valid null-terminated C string containing JSON
+#[no_mangle]
+pub unsafe extern "C" fn corint_configure_runtime(config_json: *const c_char) -> c_int {
+    let json_str = match from_c_string(config_json) {
+        Some(s) => s,
+        None => return -1,
+    };
+
+    let config: serde_json::Value = match serde_json::from_str(&json_str) {
+        Ok(v) => v,
+        Err(_) => return -1,
+    };
+
+    let read_size = |key: &str| -> Result<Option<usize>, ()> {
+        match config.get(key) {
+            None | Some(serde_json::Value::Null) => Ok(None),
+            Some(v) => match v.as_u64() {
+                Some(0) => Ok(None),
+                Some(n) => Ok(Some(n as usize)),
+                None => Err(()),
+            },
+        }
+    };
+
+    let settings = match (
+        read_size("worker_threads"),
+        read_size("thread_stack_size"),
+        read_size("log_buffer_size"),
+    ) {
+        (Ok(worker_threads), Ok(thread_stack_size), Ok(log_buffer_size)) => RuntimeSettings {
+            worker_threads,
+            thread_stack_size,
+            log_buffer_size,
+        },
+        _ => return -1,
+    };
+
+    match RUNTIME_SETTINGS.set(settings) {
+        Ok(()) => 0,
+        Err(_) => -2,
+    }
 }
 
 /// Create a new decision engine from a repository path
@@ -38,14 +137,17 @@ pub unsafe extern "C" fn corint_engine_new(repository_path: *const c_char) -> *m
         Err(_) => return ptr::null_mut(),
     };
 
-    let runtime = match tokio::runtime::Runtime::new() {
+    logging::install_logger(log_buffer_size());
+
+    let runtime = match build_runtime() {
         Ok(rt) => rt,
         Err(_) => return ptr::null_mut(),
     };
 
+    let source = RepositoryConfig::file_system(path);
     let engine = match runtime.block_on(async {
         DecisionEngineBuilder::new()
-            .with_repository(RepositoryConfig::file_system(path))
+            .with_repository(source.clone())
             .build()
             .await
     }) {
@@ -54,8 +156,10 @@ pub unsafe extern "C" fn corint_engine_new(repository_path: *const c_char) -> *m
     };
 
     Box::into_raw(Box::new(CorintEngine {
-        engine: Arc::new(engine),
+        engine: RwLock::new(Arc::new(engine)),
         runtime: Arc::new(runtime),
+        source: Some(source),
+        repositories: HashMap::new(),
     }))
 }
 
@@ -77,14 +181,17 @@ pub unsafe extern "C" fn corint_engine_new_from_database(
         Err(_) => return ptr::null_mut(),
     };
 
-    let runtime = match tokio::runtime::Runtime::new() {
+    logging::install_logger(log_buffer_size());
+
+    let runtime = match build_runtime() {
         Ok(rt) => rt,
         Err(_) => return ptr::null_mut(),
     };
 
+    let source = RepositoryConfig::database(url);
     let engine = match runtime.block_on(async {
         DecisionEngineBuilder::new()
-            .with_repository(RepositoryConfig::database(url))
+            .with_repository(source.clone())
             .build()
             .await
     }) {
@@ -93,8 +200,10 @@ pub unsafe extern "C" fn corint_engine_new_from_database(
     };
 
     Box::into_raw(Box::new(CorintEngine {
-        engine: Arc::new(engine),
+        engine: RwLock::new(Arc::new(engine)),
         runtime: Arc::new(runtime),
+        source: Some(source),
+        repositories: HashMap::new(),
     }))
 }
 
@@ -120,36 +229,450 @@ pub unsafe extern "C" fn corint_engine_decide(
         Err(_) => return ptr::null_mut(),
     };
 
-    // Parse as DecisionRequest directly, which will handle all the fields
-    let request: DecisionRequest = match serde_json::from_str(json_str) {
+    decide_json(&engine_ref.runtime, &engine_ref.current(), json_str)
+}
+
+/// Execute a decision and return the response as a chunk reader
+///
+/// Behaves like corint_engine_decide, but the response JSON is read with
+/// corint_read_chunk instead of being returned as a single C string. The
+/// reader owns the serialized response directly, so callers can decode it
+/// from bounded pieces without ever holding a full copy of their own.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new
+/// - request_json must be a valid null-terminated C string containing JSON
+/// - The returned reader must be freed with corint_response_reader_free
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_decide_chunked(
+    engine: *mut CorintEngine,
+    request_json: *const c_char,
+) -> *mut CorintResponseReader {
+    if engine.is_null() || request_json.is_null() {
+        return ptr::null_mut();
+    }
+
+    let engine_ref = &*engine;
+
+    let json_str = match CStr::from_ptr(request_json).to_str() {
+        Ok(s) => s,
+        Err(_) => return ptr::null_mut(),
+    };
+
+    match decide_bytes(&engine_ref.runtime, &engine_ref.current(), json_str) {
+        Some(data) => Box::into_raw(Box::new(CorintResponseReader { data, offset: 0 })),
+        None => ptr::null_mut(),
+    }
+}
+
+/// Get the total size in bytes of a chunked response
+///
+/// # Safety
+/// - reader must be a valid pointer returned by corint_engine_decide_chunked
+#[no_mangle]
+pub unsafe extern "C" fn corint_response_len(reader: *const CorintResponseReader) -> usize {
+    if reader.is_null() {
+        return 0;
+    }
+    (*reader).data.len()
+}
+
+/// Copy the next chunk of a response into buf
+///
+/// Returns the number of bytes written, 0 once the response is exhausted and
+/// -1 on invalid arguments.
+///
+/// # Safety
+/// - reader must be a valid pointer returned by corint_engine_decide_chunked
+/// - buf must point to at least buf_len writable bytes
+#[no_mangle]
+pub unsafe extern "C" fn corint_read_chunk(
+    reader: *mut CorintResponseReader,
+    buf: *mut u8,
+    buf_len: usize,
+) -> isize {
+    if reader.is_null() || buf.is_null() {
+        return -1;
+    }
+
+    let reader = &mut *reader;
+    let remaining = &reader.data[reader.offset..];
+    let n = remaining.len().min(buf_len).min(isize::MAX as usize);
+    ptr::copy_nonoverlapping(remaining.as_ptr(), buf, n);
+    reader.offset += n;
+    n as isize
+}
+
+/// Free a chunk reader
+///
+/// # Safety
+/// - reader must be a valid pointer returned by corint_engine_decide_chunked
+/// - After calling this function, the pointer is invalid and must not be used
+#[no_mangle]
+pub unsafe extern "C" fn corint_response_reader_free(reader: *mut CorintResponseReader) {
+    if !reader.is_null() {
+        drop(Box::from_raw(reader));
+    }
+}
+
+/// Create a new decision engine serving several named file system repositories
+///
+/// `repositories_json` is a JSON object mapping repository names to paths.
+/// corint_engine_decide uses the repository named "default" if present, and
+/// otherwise the first name in lexicographic order.
+///
+/// # Safety
+/// - repositories_json must be a valid null-terminated C string containing JSON
+/// - The returned pointer must be freed with corint_engine_free
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_new_multi(
+    repositories_json: *const c_char,
+) -> *mut CorintEngine {
+    let json_str = match from_c_string(repositories_json) {
+        Some(s) => s,
+        None => return ptr::null_mut(),
+    };
+
+    let paths: BTreeMap<String, String> = match serde_json::from_str(&json_str) {
         Ok(v) => v,
         Err(_) => return ptr::null_mut(),
     };
+    if paths.is_empty() {
+        return ptr::null_mut();
+    }
 
-    let result = match engine_ref.runtime.block_on(async {
-        engine_ref.engine.decide(request).await
-    }) {
+    logging::install_logger(log_buffer_size());
+
+    let runtime = match build_runtime() {
+        Ok(rt) => rt,
+        Err(_) => return ptr::null_mut(),
+    };
+
+    let mut repositories = HashMap::with_capacity(paths.len());
+    for (name, path) in &paths {
+        let engine = match runtime.block_on(async {
+            DecisionEngineBuilder::new()
+                .with_repository(RepositoryConfig::file_system(path))
+                .build()
+                .await
+        }) {
+            Ok(e) => e,
+            Err(_) => return ptr::null_mut(),
+        };
+        repositories.insert(name.clone(), Arc::new(engine));
+    }
+
+    let default_name = if paths.contains_key("default") {
+        "default"
+    } else {
+        paths.keys().next().map(String::as_str).unwrap_or_default()
+    };
+    let default_engine = match repositories.get(default_name) {
+        Some(e) => Arc::clone(e),
+        None => return ptr::null_mut(),
+    };
+
+    Box::into_raw(Box::new(CorintEngine {
+        engine: RwLock::new(default_engine),
+        runtime: Arc::new(runtime),
+        source: None,
+        repositories,
+    }))
+}
+
+/// Execute a decision against a named repository of a multi-repository engine
+///
+/// Returns an error response with `"error_code": "unknown_repository"` when no
+/// repository with that name was loaded.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new_multi
+/// - repository and request_json must be valid null-terminated C strings
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_decide_in_repository(
+    engine: *mut CorintEngine,
+    repository: *const c_char,
+    request_json: *const c_char,
+) -> *mut c_char {
+    if engine.is_null() {
+        return ptr::null_mut();
+    }
+
+    let engine_ref = &*engine;
+
+    let (name, json_str) = match (from_c_string(repository), from_c_string(request_json)) {
+        (Some(name), Some(json_str)) => (name, json_str),
+        _ => return ptr::null_mut(),
+    };
+
+    match engine_ref.repositories.get(&name) {
+        Some(target) => decide_json(&engine_ref.runtime, target, &json_str),
+        None => error_json(
+            &format!("unknown repository: {}", name),
+            Some("unknown_repository"),
+        ),
+    }
+}
+
+/// Get the compiled program of a rule as JSON
+///
+/// Returns an error response with `"error_code": "rule_not_found"` when the
+/// engine has no rule with that ID.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new
+/// - rule_id must be a valid null-terminated C string
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_rule_ast(
+    engine: *mut CorintEngine,
+    rule_id: *const c_char,
+) -> *mut c_char {
+    if engine.is_null() {
+        return ptr::null_mut();
+    }
+
+    let rule_id = match from_c_string(rule_id) {
+        Some(s) => s,
+        None => return ptr::null_mut(),
+    };
+
+    let current = (*engine).current();
+    match current.rule_program(&rule_id) {
+        Some(program) => match serde_json::to_string(program) {
+            Ok(s) => to_c_string(&s),
+            Err(e) => error_json(&format!("Failed to serialize rule: {}", e), None),
+        },
+        None => error_json(
+            &format!("rule not found: {}", rule_id),
+            Some("rule_not_found"),
+        ),
+    }
+}
+
+/// Get the names of the request features a rule reads as a JSON array
+///
+/// Returns an error response with `"error_code": "rule_not_found"` when the
+/// engine has no rule with that ID.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new
+/// - rule_id must be a valid null-terminated C string
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_required_features(
+    engine: *mut CorintEngine,
+    rule_id: *const c_char,
+) -> *mut c_char {
+    if engine.is_null() {
+        return ptr::null_mut();
+    }
+
+    let rule_id = match from_c_string(rule_id) {
+        Some(s) => s,
+        None => return ptr::null_mut(),
+    };
+
+    let current = (*engine).current();
+    match current.rule_program(&rule_id) {
+        Some(program) => match serde_json::to_string(&required_features(program)) {
+            Ok(s) => to_c_string(&s),
+            Err(_) => ptr::null_mut(),
+        },
+        None => error_json(
+            &format!("rule not found: {}", rule_id),
+            Some("rule_not_found"),
+        ),
+    }
+}
+
+/// Build a JSON error response string
+fn error_json(message: &str, error_code: Option<&str>) -> *mut c_char {
+    let mut error_response = serde_json::json!({
+        "error": message,
+        "success": false
+    });
+    if let Some(code) = error_code {
+        error_response["error_code"] = serde_json::Value::from(code);
+    }
+    match serde_json::to_string(&error_response) {
+        Ok(s) => to_c_string(&s),
+        Err(_) => ptr::null_mut(),
+    }
+}
+
+/// Build the JSON error response for a failed decision, including diagnostics
+fn decision_error_json(error: &SdkError) -> serde_json::Value {
+    let error_kind = match error {
+        SdkError::ConfigError(_) | SdkError::Config(_) => "config",
+        SdkError::ParseError(_) => "parse",
+        SdkError::CompileError(_) => "compile",
+        SdkError::RuntimeError(_) => "runtime",
+        SdkError::IoError(_) => "io",
+        SdkError::InvalidRuleFile(_) => "invalid_rule_file",
+        SdkError::NotInitialized => "not_initialized",
+        SdkError::GenericError(_) => "generic",
+    };
+
+    serde_json::json!({
+        "error": error.to_string(),
+        "success": false,
+        "diagnostics": {
+            "error_kind": error_kind,
+            "native_version": env!("CARGO_PKG_VERSION"),
+        }
+    })
+}
+
+/// Execute a JSON-encoded decision request and return the JSON response
+fn decide_json(runtime: &Runtime, engine: &DecisionEngine, json_str: &str) -> *mut c_char {
+    match decide_bytes(runtime, engine, json_str) {
+        Some(response) => bytes_to_c_string(response),
+        None => ptr::null_mut(),
+    }
+}
+
+/// Execute a JSON-encoded decision request and return the serialized JSON
+/// response, or None when the request cannot be decoded
+fn decide_bytes(runtime: &Runtime, engine: &DecisionEngine, json_str: &str) -> Option<Vec<u8>> {
+    let raw_request: serde_json::Value = serde_json::from_str(json_str).ok()?;
+
+    // Binding-level options that the SDK request type does not carry
+    let max_trace_bytes = raw_request
+        .get("options")
+        .and_then(|o| o.get("max_trace_bytes"))
+        .and_then(|v| v.as_u64())
+        .filter(|v| *v > 0)
+        .map(|v| v as usize);
+    let first_action_only = raw_request
+        .get("options")
+        .and_then(|o| o.get("first_action_only"))
+        .and_then(|v| v.as_bool())
+        .unwrap_or(false);
+
+    // Parse as DecisionRequest, which will handle all the remaining fields
+    let request: DecisionRequest = serde_json::from_value(raw_request).ok()?;
+
+    let result = match runtime.block_on(async { engine.decide(request).await }) {
         Ok(r) => r,
-        Err(e) => {
-            let error_response = serde_json::json!({
-                "error": e.to_string(),
-                "success": false
-            });
-            match serde_json::to_string(&error_response) {
-                Ok(s) => return CString::new(s).unwrap().into_raw(),
-                Err(_) => return ptr::null_mut(),
-            }
+        Err(e) => return serde_json::to_vec(&decision_error_json(&e)).ok(),
+    };
+
+    let mut response_value = serde_json::to_value(&result).ok()?;
+
+    if first_action_only {
+        // Actions keep the order the matched conclusion declares them in,
+        // which rulesets use as priority order, so keep only the first one
+        if let Some(actions) = response_value
+            .get_mut("result")
+            .and_then(|r| r.get_mut("actions"))
+            .and_then(|a| a.as_array_mut())
+        {
+            actions.truncate(1);
         }
+    }
+
+    if let (Some(max_bytes), Some(trace)) = (max_trace_bytes, response_value.get_mut("trace")) {
+        truncate_trace(trace, max_bytes);
+    }
+
+    serde_json::to_vec(&response_value).ok()
+}
+
+/// Reload the engine's repository without interrupting decisions
+///
+/// Equivalent to corint_engine_reload_with_options with rule toggles kept.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new or
+///   corint_engine_new_from_database
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_reload(engine: *mut CorintEngine) -> c_int {
+    corint_engine_reload_with_options(engine, 0)
+}
+
+/// Reload the engine's repository, optionally clearing rule toggles
+///
+/// The new engine state is built while decisions keep using the current one,
+/// then swapped in. The previous state is freed once in-flight decisions that
+/// still use it complete. Unless clear_rule_toggles is non-zero, rules
+/// disabled with corint_engine_set_rule_enabled stay disabled in the new
+/// state if they still exist. Returns 0 on success, -1 if the new state failed
+/// to build (the current state is kept) and -2 if the engine cannot be
+/// reloaded (multi-repository engines).
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new or
+///   corint_engine_new_from_database
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_reload_with_options(
+    engine: *mut CorintEngine,
+    clear_rule_toggles: c_int,
+) -> c_int {
+    if engine.is_null() {
+        return -1;
+    }
+
+    let engine_ref = &*engine;
+
+    let source = match &engine_ref.source {
+        Some(source) => source.clone(),
+        None => return -2,
     };
 
-    let response_json = match serde_json::to_string(&result) {
-        Ok(s) => s,
-        Err(_) => return ptr::null_mut(),
+    let reloaded = match engine_ref.runtime.block_on(async {
+        DecisionEngineBuilder::new()
+            .with_repository(source)
+            .build()
+            .await
+    }) {
+        Ok(e) => e,
+        Err(_) => return -1,
     };
 
-    match CString::new(response_json) {
-        Ok(s) => s.into_raw(),
-        Err(_) => ptr::null_mut(),
+    // Toggles are copied under the write lock: corint_engine_set_rule_enabled
+    // holds the read lock while setting, so none can land on the old state
+    // after it was copied
+    let mut current = engine_ref.engine.write().unwrap_or_else(|e| e.into_inner());
+    if clear_rule_toggles == 0 {
+        for rule_id in current.disabled_rules() {
+            let _ = reloaded.set_rule_enabled(&rule_id, false);
+        }
+    }
+    *current = Arc::new(reloaded);
+    0
+}
+
+/// Enable or disable a rule for all subsequent decisions
+///
+/// The toggle is kept across corint_engine_reload for rules that still exist
+/// after reloading, unless the reload clears rule toggles. Returns 0 on success, -1 on invalid arguments and -2 if
+/// the engine has no rule with that ID.
+///
+/// # Safety
+/// - engine must be a valid pointer created by corint_engine_new
+/// - rule_id must be a valid null-terminated C string
+#[no_mangle]
+pub unsafe extern "C" fn corint_engine_set_rule_enabled(
+    engine: *mut CorintEngine,
+    rule_id: *const c_char,
+    enabled: c_int,
+) -> c_int {
+    if engine.is_null() {
+        return -1;
+    }
+
+    let rule_id = match from_c_string(rule_id) {
+        Some(s) => s,
+        None => return -1,
+    };
+
+    // Hold the read lock while setting so a concurrent reload copies the toggle
+    let current = (*engine).engine.read().unwrap_or_else(|e| e.into_inner());
+    match current.set_rule_enabled(&rule_id, enabled != 0) {
+        Ok(()) => 0,
+        Err(_) => -2,
     }
 }
 
@@ -173,19 +696,86 @@ pub unsafe extern "C" fn corint_engine_free(engine: *mut CorintEngine) {
 #[no_mangle]
 pub unsafe extern "C" fn corint_string_free(s: *mut c_char) {
     if !s.is_null() {
-        drop(CString::from_raw(s));
+        drop(take_c_string(s));
     }
 }
 
+/// Get the number of strings returned by the FFI that have not been freed
+///
+/// Intended for leak checks in binding tests: the count returns to its
+/// previous value once every returned string is passed to corint_string_free.
+#[no_mangle]
+pub extern "C" fn corint_live_strings() -> usize {
+    live_strings()
+}
+
 /// Get the version of the CORINT library
 ///
 /// # Safety
 /// - The returned string must be freed with corint_string_free
 #[no_mangle]
 pub extern "C" fn corint_version() -> *mut c_char {
-    let version = env!("CARGO_PKG_VERSION");
-    match CString::new(version) {
-        Ok(s) => s.into_raw(),
+    to_c_string(env!("CARGO_PKG_VERSION"))
+}
+
+/// Version of the C ABI exposed by this library
+///
+/// Bumped whenever an exported function changes signature or semantics.
+const ABI_VERSION: u32 = 1;
+
+/// Optional features of this library that bindings may probe for
+const CAPABILITIES: &[&str] = &["chunked_responses"];
+
+/// Get build details of the CORINT library as JSON
+///
+/// Returns an object with `version`, `git_commit` (from `CORINT_GIT_COMMIT`
+/// at build time, empty if unset), `build_features`, `abi_version` and
+/// `capabilities`, the optional features bindings may rely on.
+///
+/// # Safety
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub extern "C" fn corint_version_info() -> *mut c_char {
+    let mut build_features = Vec::new();
+    if cfg!(feature = "sqlx") {
+        build_features.push("sqlx");
+    }
+    if cfg!(feature = "redis") {
+        build_features.push("redis");
+    }
+    if cfg!(debug_assertions) {
+        build_features.push("debug");
+    }
+
+    let info = serde_json::json!({
+        "version": env!("CARGO_PKG_VERSION"),
+        "git_commit": option_env!("CORINT_GIT_COMMIT").unwrap_or(""),
+        "build_features": build_features,
+        "abi_version": ABI_VERSION,
+        "capabilities": CAPABILITIES,
+    });
+    match serde_json::to_string(&info) {
+        Ok(s) => to_c_string(&s),
+        Err(_) => ptr::null_mut(),
+    }
+}
+
+/// Report the ABI contract of this library as JSON
+///
+/// Returns an object with `abi_version`, `pointer_size` and `c_int_size` so
+/// bindings can verify at startup that they were built for this library.
+///
+/// # Safety
+/// - The returned string must be freed with corint_string_free
+#[no_mangle]
+pub extern "C" fn corint_abi_check() -> *mut c_char {
+    let contract = serde_json::json!({
+        "abi_version": ABI_VERSION,
+        "pointer_size": std::mem::size_of::<*const c_char>(),
+        "c_int_size": std::mem::size_of::<c_int>(),
+    });
+    match serde_json::to_string(&contract) {
+        Ok(s) => to_c_string(&s),
         Err(_) => ptr::null_mut(),
     }
 }
@@ -193,6 +783,102 @@ pub extern "C" fn corint_version() -> *mut c_char {
 #[cfg(test)]
 mod tests {
     use super::*;
+    use std::ffi::CString;
+    use std::path::{Path, PathBuf};
+
+    /// Create an empty fixture repository directory unique to this test
+    fn fixture_repository(name: &str) -> PathBuf {
+        let root = std::env::temp_dir().join(format!("corint_ffi_{}_{}", name, std::process::id()));
+        let _ = std::fs::remove_dir_all(&root);
+        std::fs::create_dir_all(root.join("pipelines")).unwrap();
+        std::fs::write(
+            root.join("registry.yaml"),
+            r#"version: "0.1"
+registry:
+  - pipeline: login_pipeline
+    when: event.type == "login"
+"#,
+        )
+        .unwrap();
+        root
+    }
+
+    /// Write the login pipeline, declining high amounts with `signal`
+    fn write_login_pipeline(root: &Path, signal: &str) {
+        let pipeline = format!(
+            r#"pipeline:
+  id: login_pipeline
+  name: Login Pipeline
+  when:
+    event.type: login
+  steps:
+  - include:
+      ruleset: login_risk
+
+---
+
+rule:
+  id: high_amount
+  name: High Amount
+  when:
+    conditions:
+    - event.amount > 100
+  score: 100
+
+---
+
+ruleset:
+  id: login_risk
+  rules:
+  - high_amount
+  conclusion:
+  - when: total_score >= 100
+    signal: {}
+  - default: true
+    signal: approve
+"#,
+            signal
+        );
+        std::fs::write(root.join("pipelines/login.yaml"), pipeline).unwrap();
+    }
+
+    /// Create an engine serving the fixture repository at root
+    fn fixture_engine(root: &Path) -> *mut CorintEngine {
+        let path = CString::new(root.to_str().unwrap()).unwrap();
+        let engine = unsafe { corint_engine_new(path.as_ptr()) };
+        assert!(!engine.is_null(), "engine creation failed for {:?}", root);
+        engine
+    }
+
+    /// Run a decision on engine and return the decoded response
+    fn decide(engine: *mut CorintEngine, request: &str) -> serde_json::Value {
+        let request = CString::new(request).unwrap();
+        unsafe {
+            let response = corint_engine_decide(engine, request.as_ptr());
+            assert!(!response.is_null(), "decision returned no response");
+            let value = serde_json::from_str(CStr::from_ptr(response).to_str().unwrap()).unwrap();
+            corint_string_free(response);
+            value
+        }
+    }
+
+    /// Run a per-rule lookup on engine and return the decoded result
+    fn query_rule(
+        engine: *mut CorintEngine,
+        query: unsafe extern "C" fn(*mut CorintEngine, *const c_char) -> *mut c_char,
+        rule_id: &str,
+    ) -> serde_json::Value {
+        let rule_id = CString::new(rule_id).unwrap();
+        unsafe {
+            let result = query(engine, rule_id.as_ptr());
+            assert!(!result.is_null(), "rule lookup returned no result");
+            let value = serde_json::from_str(CStr::from_ptr(result).to_str().unwrap()).unwrap();
+            corint_string_free(result);
+            value
+        }
+    }
+
+    const HIGH_AMOUNT_LOGIN: &str = r#"{"event_data":{"type":"login","amount":500}}"#;
 
     #[test]
     fn test_version() {
@@ -204,4 +890,216 @@ mod tests {
             corint_string_free(version);
         }
     }
+
+    #[test]
+    fn test_version_info() {
+        unsafe {
+            let info = corint_version_info();
+            assert!(!info.is_null());
+            let info_str = CStr::from_ptr(info).to_str().unwrap();
+            let value: serde_json::Value = serde_json::from_str(info_str).unwrap();
+            assert_eq!(value["version"], env!("CARGO_PKG_VERSION"));
+            assert_eq!(value["abi_version"], ABI_VERSION);
+            assert_eq!(
+                value["capabilities"],
+                serde_json::json!(["chunked_responses"])
+            );
+            corint_string_free(info);
+        }
+    }
+
+    #[test]
+    fn test_reload_during_decisions() {
+        let root = fixture_repository("reload");
+        write_login_pipeline(&root, "decline");
+        let engine = fixture_engine(&root);
+        assert_eq!(
+            decide(engine, HIGH_AMOUNT_LOGIN)["result"]["signal"]["type"],
+            "decline"
+        );
+
+        // Raw pointers are not Send; the engine outlives the scope below
+        let handle = engine as usize;
+        let stop = std::sync::atomic::AtomicBool::new(false);
+        std::thread::scope(|scope| {
+            let workers: Vec<_> = (0..4)
+                .map(|_| {
+                    scope.spawn(|| {
+                        let mut decisions = 0;
+                        while !stop.load(std::sync::atomic::Ordering::Relaxed) {
+                            let response = decide(handle as *mut CorintEngine, HIGH_AMOUNT_LOGIN);
+                            assert!(
+                                response.get("error").is_none(),
+                                "decision failed: {}",
+                                response
+                            );
+                            let signal = response["result"]["signal"]["type"].clone();
+                            assert!(
+                                signal == "decline" || signal == "review",
+                                "unexpected signal {}",
+                                signal
+                            );
+                            decisions += 1;
+                        }
+                        decisions
+                    })
+                })
+                .collect();
+
+            write_login_pipeline(&root, "review");
+            assert_eq!(
+                unsafe { corint_engine_reload(handle as *mut CorintEngine) },
+                0
+            );
+
+            // Every decision started after the reload returned sees the new state
+            for _ in 0..20 {
+                let response = decide(handle as *mut CorintEngine, HIGH_AMOUNT_LOGIN);
+                assert_eq!(response["result"]["signal"]["type"], "review");
+            }
+
+            stop.store(true, std::sync::atomic::Ordering::Relaxed);
+            for worker in workers {
+                assert!(worker.join().unwrap() > 0);
+            }
+        });
+
+        unsafe { corint_engine_free(engine) };
+        let _ = std::fs::remove_dir_all(&root);
+    }
+
+    #[test]
+    fn test_rule_ast() {
+        let root = fixture_repository("rule_ast");
+        write_login_pipeline(&root, "decline");
+        let engine = fixture_engine(&root);
+
+        let program = query_rule(engine, corint_engine_rule_ast, "high_amount");
+        assert!(program.get("error").is_none(), "lookup failed: {}", program);
+        assert!(program.is_object());
+
+        let missing = query_rule(engine, corint_engine_rule_ast, "missing");
+        assert_eq!(missing["error_code"], "rule_not_found");
+
+        unsafe { corint_engine_free(engine) };
+        let _ = std::fs::remove_dir_all(&root);
+    }
+
+    #[test]
+    fn test_reload_rule_toggles() {
+        let root = fixture_repository("reload_toggles");
+        write_login_pipeline(&root, "decline");
+        let engine = fixture_engine(&root);
+        let signal = || decide(engine, HIGH_AMOUNT_LOGIN)["result"]["signal"]["type"].clone();
+
+        let rule_id = CString::new("high_amount").unwrap();
+        unsafe {
+            assert_eq!(
+                corint_engine_set_rule_enabled(engine, rule_id.as_ptr(), 0),
+                0
+            );
+        }
+        assert_eq!(signal(), "approve");
+
+        assert_eq!(unsafe { corint_engine_reload(engine) }, 0);
+        assert_eq!(signal(), "approve", "reload dropped the rule toggle");
+
+        assert_eq!(unsafe { corint_engine_reload_with_options(engine, 1) }, 0);
+        assert_eq!(signal(), "decline", "clearing reload kept the rule toggle");
+
+        unsafe { corint_engine_free(engine) };
+        let _ = std::fs::remove_dir_all(&root);
+    }
+
+    #[test]
+    fn test_decide_chunked() {
+        let root = fixture_repository("chunked");
+        write_login_pipeline(&root, "decline");
+        let engine = fixture_engine(&root);
+
+        let request = CString::new(HIGH_AMOUNT_LOGIN).unwrap();
+        let mut data = Vec::new();
+        unsafe {
+            let reader = corint_engine_decide_chunked(engine, request.as_ptr());
+            assert!(!reader.is_null(), "chunked decision returned no reader");
+            let total = corint_response_len(reader);
+
+            let mut buf = [0u8; 7];
+            loop {
+                let n = corint_read_chunk(reader, buf.as_mut_ptr(), buf.len());
+                assert!(n >= 0, "chunk read failed");
+                if n == 0 {
+                    break;
+                }
+                data.extend_from_slice(&buf[..n as usize]);
+            }
+            assert_eq!(data.len(), total);
+            corint_response_reader_free(reader);
+        }
+
+        let chunked: serde_json::Value = serde_json::from_slice(&data).unwrap();
+        assert_eq!(chunked["result"]["signal"]["type"], "decline");
+        assert_eq!(
+            chunked["result"]["triggered_rules"],
+            decide(engine, HIGH_AMOUNT_LOGIN)["result"]["triggered_rules"]
+        );
+
+        unsafe { corint_engine_free(engine) };
+        let _ = std::fs::remove_dir_all(&root);
+    }
+
+    #[test]
+    fn test_required_features_from_fixture() {
+        let root = fixture_repository("required_features");
+        std::fs::write(
+            root.join("pipelines/login.yaml"),
+            r#"pipeline:
+  id: login_pipeline
+  name: Login Pipeline
+  when:
+    event.type: login
+  steps:
+  - include:
+      ruleset: login_risk
+
+---
+
+rule:
+  id: velocity
+  name: Velocity
+  when:
+    conditions:
+    - features.txn_count_24h >= 10
+    - features.country_changed == true
+    - event.amount > 100
+  score: 100
+
+---
+
+ruleset:
+  id: login_risk
+  rules:
+  - velocity
+  conclusion:
+  - when: total_score >= 100
+    signal: decline
+  - default: true
+    signal: approve
+"#,
+        )
+        .unwrap();
+        let engine = fixture_engine(&root);
+
+        let features = query_rule(engine, corint_engine_required_features, "velocity");
+        assert_eq!(
+            features,
+            serde_json::json!(["country_changed", "txn_count_24h"])
+        );
+
+        let missing = query_rule(engine, corint_engine_required_features, "missing");
+        assert_eq!(missing["error_code"], "rule_not_found");
+
+        unsafe { corint_engine_free(engine) };
+        let _ = std::fs::remove_dir_all(&root);
+    }
 }
@@ -0,0 +1,116 @@
+//! Logging setup and in-memory buffer of recent log lines
+
+use std::collections::VecDeque;
+use std::sync::{Mutex, Once, OnceLock};
+
+use log::{LevelFilter, Log, Metadata, Record};
+
+/// Number of log lines retained when no buffer size is configured
+pub const DEFAULT_LOG_BUFFER_SIZE: usize = 1000;
+
+/// Fixed-capacity buffer holding the most recent log lines
+pub struct LogRingBuffer {
+    capacity: usize,
+    lines: Mutex<VecDeque<String>>,
+}
+
+impl LogRingBuffer {
+    /// Create a buffer retaining at most `capacity` lines
+    pub fn new(capacity: usize) -> Self {
+        Self {
+            capacity,
+            lines: Mutex::new(VecDeque::with_capacity(capacity)),
+        }
+    }
+
+    /// Append a line, dropping the oldest one when the buffer is full
+    pub fn push(&self, line: String) {
+        if self.capacity == 0 {
+            return;
+        }
+        let mut lines = self.lines.lock().unwrap_or_else(|e| e.into_inner());
+        if lines.len() == self.capacity {
+            lines.pop_front();
+        }
+        lines.push_back(line);
+    }
+
+    /// Return up to `n` of the most recent lines, oldest first
+    pub fn recent(&self, n: usize) -> Vec<String> {
+        let lines = self.lines.lock().unwrap_or_else(|e| e.into_inner());
+        let skip = lines.len().saturating_sub(n);
+        lines.iter().skip(skip).cloned().collect()
+    }
+}
+
+/// Logger that forwards to env_logger and records lines in the ring buffer
+struct BufferedLogger {
+    inner: env_logger::Logger,
+    buffer: &'static LogRingBuffer,
+}
+
+impl Log for BufferedLogger {
+    fn enabled(&self, metadata: &Metadata) -> bool {
+        metadata.level() <= LevelFilter::Info || self.inner.enabled(metadata)
+    }
+
+    fn log(&self, record: &Record) {
+        if record.level() <= LevelFilter::Info || self.inner.enabled(record.metadata()) {
+            self.buffer.push(format!(
+                "[{} {}] {}",
+                record.level(),
+                record.target(),
+                record.args()
+            ));
+        }
+        if self.inner.matches(record) {
+            self.inner.log(record);
+        }
+    }
+
+    fn flush(&self) {
+        self.inner.flush();
+    }
+}
+
+static LOG_BUFFER: OnceLock<LogRingBuffer> = OnceLock::new();
+static INSTALL_LOGGER: Once = Once::new();
+
+/// Return the process-wide log buffer, creating it with `capacity` on first use
+pub fn log_buffer(capacity: usize) -> &'static LogRingBuffer {
+    LOG_BUFFER.get_or_init(|| LogRingBuffer::new(capacity))
+}
+
+/// Install the buffered logger once; later calls are no-ops
+///
+/// Log lines at info level and above are always buffered, while output to
+/// stderr keeps following the RUST_LOG filter.
+pub fn install_logger(buffer_capacity: usize) {
+    INSTALL_LOGGER.call_once(|| {
+        let inner = env_logger::Builder::from_default_env().build();
+        let max_level = inner.filter().max(LevelFilter::Info);
+        let logger = BufferedLogger {
+            inner,
+            buffer: log_buffer(buffer_capacity),
+        };
+        if log::set_boxed_logger(Box::new(logger)).is_ok() {
+            log::set_max_level(max_level);
+        }
+    });
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_ring_buffer_keeps_most_recent_lines() {
+        let buffer = LogRingBuffer::new(2);
+        buffer.push("first".to_string());
+        buffer.push("second".to_string());
+        buffer.push("third".to_string());
+
+        assert_eq!(buffer.recent(5), vec!["second", "third"]);
+        assert_eq!(buffer.recent(1), vec!["third"]);
+    }
+}
@@ -1,12 +1,46 @@
 //! FFI type definitions
 
-use std::sync::Arc;
-use corint_sdk::DecisionEngine;
+use std::collections::HashMap;
+use std::sync::{Arc, RwLock};
+use corint_sdk::{DecisionEngine, RepositoryConfig};
 use tokio::runtime::Runtime;
 
 /// Opaque type representing a CORINT decision engine
 #[repr(C)]
 pub struct CorintEngine {
-    pub(crate) engine: Arc<DecisionEngine>,
+    /// Current engine state, swapped atomically by corint_engine_reload
+    pub(crate) engine: RwLock<Arc<DecisionEngine>>,
     pub(crate) runtime: Arc<Runtime>,
+    /// Repository the engine was built from, used to rebuild it on reload
+    pub(crate) source: Option<RepositoryConfig>,
+    /// Named repositories, populated by corint_engine_new_multi
+    pub(crate) repositories: HashMap<String, Arc<DecisionEngine>>,
+}
+
+impl CorintEngine {
+    /// Return the current engine state
+    ///
+    /// Callers keep using the returned state even if a reload swaps in a new
+    /// one; the old state is freed once the last in-flight decision drops it.
+    pub(crate) fn current(&self) -> Arc<DecisionEngine> {
+        let engine = self.engine.read().unwrap_or_else(|e| e.into_inner());
+        Arc::clone(&engine)
+    }
+}
+
+/// Decision response read back in chunks through corint_read_chunk
+pub struct CorintResponseReader {
+    pub(crate) data: Vec<u8>,
+    pub(crate) offset: usize,
+}
+
+/// Native runtime settings applied to every engine created after configuration
+#[derive(Debug, Clone, Default)]
+pub struct RuntimeSettings {
+    /// Number of runtime worker threads (defaults to the number of CPUs)
+    pub worker_threads: Option<usize>,
+    /// Stack size in bytes for each worker thread
+    pub thread_stack_size: Option<usize>,
+    /// Number of recent log lines retained for corint_recent_logs
+    pub log_buffer_size: Option<usize>,
 }
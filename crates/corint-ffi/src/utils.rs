@@ -1,16 +1,49 @@
 //! FFI utility functions
 
+use std::collections::BTreeSet;
 use std::ffi::{CStr, CString};
 use std::os::raw::c_char;
+use std::sync::atomic::{AtomicUsize, Ordering};
+
+use corint_core::ir::{Instruction, Program};
+
+/// Number of C strings handed out by bytes_to_c_string and not yet taken back
+static LIVE_STRINGS: AtomicUsize = AtomicUsize::new(0);
 
 /// Helper to convert Rust string to C string
 pub fn to_c_string(s: &str) -> *mut c_char {
-    match CString::new(s) {
-        Ok(cs) => cs.into_raw(),
+    bytes_to_c_string(s.as_bytes().to_vec())
+}
+
+/// Convert owned bytes to a C string without copying them again
+///
+/// Every string returned to callers goes through this function so that
+/// corint_live_strings can report strings that were never freed.
+pub fn bytes_to_c_string(bytes: Vec<u8>) -> *mut c_char {
+    match CString::new(bytes) {
+        Ok(cs) => {
+            LIVE_STRINGS.fetch_add(1, Ordering::Relaxed);
+            cs.into_raw()
+        }
         Err(_) => std::ptr::null_mut(),
     }
 }
 
+/// Take back ownership of a string returned by bytes_to_c_string
+///
+/// # Safety
+/// - s must be a non-null pointer returned by bytes_to_c_string that has not
+///   been taken back already
+pub unsafe fn take_c_string(s: *mut c_char) -> CString {
+    LIVE_STRINGS.fetch_sub(1, Ordering::Relaxed);
+    CString::from_raw(s)
+}
+
+/// Number of strings returned to callers that have not been freed yet
+pub fn live_strings() -> usize {
+    LIVE_STRINGS.load(Ordering::Relaxed)
+}
+
 /// Helper to convert C string to Rust string
 pub unsafe fn from_c_string(s: *const c_char) -> Option<String> {
     if s.is_null() {
@@ -18,3 +51,172 @@ pub unsafe fn from_c_string(s: *const c_char) -> Option<String> {
     }
     CStr::from_ptr(s).to_str().ok().map(|s| s.to_owned())
 }
+
+/// Shrink a serialized execution trace so it fits within `max_bytes`
+///
+/// Condition details are dropped first since they dominate trace size; if the
+/// trace still does not fit it is replaced by a stub. Truncated traces are
+/// marked with `"truncated": true`. Returns whether the trace was truncated.
+pub fn truncate_trace(trace: &mut serde_json::Value, max_bytes: usize) -> bool {
+    let original_bytes = serialized_len(trace);
+    if original_bytes <= max_bytes {
+        return false;
+    }
+
+    strip_conditions(trace);
+    if let Some(obj) = trace.as_object_mut() {
+        obj.insert("truncated".to_string(), serde_json::Value::Bool(true));
+        obj.insert("original_bytes".to_string(), original_bytes.into());
+    }
+
+    if serialized_len(trace) > max_bytes {
+        *trace = serde_json::json!({
+            "truncated": true,
+            "original_bytes": original_bytes,
+        });
+    }
+    true
+}
+
+fn serialized_len(value: &serde_json::Value) -> usize {
+    serde_json::to_vec(value)
+        .map(|v| v.len())
+        .unwrap_or(usize::MAX)
+}
+
+/// Remove condition evaluation details from every level of a trace
+fn strip_conditions(value: &mut serde_json::Value) {
+    match value {
+        serde_json::Value::Object(obj) => {
+            for key in [
+                "conditions",
+                "when_conditions",
+                "branch_conditions",
+                "nested",
+            ] {
+                obj.remove(key);
+            }
+            for child in obj.values_mut() {
+                strip_conditions(child);
+            }
+        }
+        serde_json::Value::Array(items) => {
+            for item in items {
+                strip_conditions(item);
+            }
+        }
+        _ => {}
+    }
+}
+
+/// Collect the request features a compiled program reads, sorted by name
+pub fn required_features(program: &Program) -> Vec<String> {
+    let decision_instructions = program.decision_instructions.iter().flatten();
+    let names: BTreeSet<String> = program
+        .instructions
+        .iter()
+        .chain(decision_instructions)
+        .filter_map(|instruction| match instruction {
+            Instruction::LoadField { path } if path.len() >= 2 && path[0] == "features" => {
+                Some(path[1].clone())
+            }
+            _ => None,
+        })
+        .collect();
+    names.into_iter().collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use corint_core::ir::ProgramMetadata;
+
+    /// A trace with one ruleset of `rules` rules, each carrying a condition
+    fn large_trace(rules: usize) -> serde_json::Value {
+        let rules: Vec<serde_json::Value> = (0..rules)
+            .map(|i| {
+                serde_json::json!({
+                    "rule_id": format!("rule_{}", i),
+                    "triggered": false,
+                    "conditions": [{
+                        "expression": format!("event.amount > {}", i),
+                        "left_value": i,
+                        "right_value": i + 1,
+                        "result": false,
+                    }],
+                })
+            })
+            .collect();
+        serde_json::json!({
+            "pipeline": {
+                "pipeline_id": "test_pipeline",
+                "rulesets": [{"ruleset_id": "test_ruleset", "rules": rules}],
+            }
+        })
+    }
+
+    #[test]
+    fn test_truncate_trace_within_limit() {
+        let mut trace = large_trace(2);
+        let original = trace.clone();
+
+        assert!(!truncate_trace(&mut trace, 1 << 20));
+        assert_eq!(trace, original);
+    }
+
+    #[test]
+    fn test_truncate_trace_strips_conditions() {
+        let mut trace = large_trace(200);
+        let original_bytes = serialized_len(&trace);
+        let stripped_bytes = {
+            let mut stripped = trace.clone();
+            strip_conditions(&mut stripped);
+            serialized_len(&stripped)
+        };
+        let max_bytes = stripped_bytes + 64;
+
+        assert!(truncate_trace(&mut trace, max_bytes));
+        assert!(serialized_len(&trace) <= max_bytes);
+        assert_eq!(trace["truncated"], true);
+        assert_eq!(trace["original_bytes"], original_bytes);
+
+        let rules = trace["pipeline"]["rulesets"][0]["rules"]
+            .as_array()
+            .unwrap();
+        assert_eq!(rules.len(), 200);
+        assert!(rules.iter().all(|rule| rule.get("conditions").is_none()));
+    }
+
+    #[test]
+    fn test_truncate_trace_replaces_oversized_trace() {
+        let mut trace = large_trace(200);
+        let original_bytes = serialized_len(&trace);
+
+        assert!(truncate_trace(&mut trace, 64));
+        assert_eq!(
+            trace,
+            serde_json::json!({"truncated": true, "original_bytes": original_bytes})
+        );
+    }
+
+    #[test]
+    fn test_required_features() {
+        let load = |path: &[&str]| Instruction::LoadField {
+            path: path.iter().map(|s| s.to_string()).collect(),
+        };
+        let program = Program::new(
+            vec![
+                load(&["features", "txn_count_24h"]),
+                load(&["event", "amount"]),
+                load(&["features", "avg_amount"]),
+                load(&["features", "txn_count_24h"]),
+            ],
+            ProgramMetadata::for_rule("velocity".to_string()),
+        );
+
+        assert_eq!(
+            required_features(&program),
+            vec!["avg_amount".to_string(), "txn_count_24h".to_string()]
+        );
+    }
+}
@@ -5,6 +5,7 @@
 
 use crate::error::{Result, RuntimeError};
 use crate::result::{DecisionResult, ExecutionResult};
+use chrono::{DateTime, Utc};
 use corint_core::ast::Signal;
 use corint_core::Value;
 use std::collections::HashMap;
@@ -24,6 +25,8 @@ pub struct ContextInput {
     pub llm: Option<HashMap<String, Value>>,
     /// Simple variables and intermediate calculations (optional)
     pub vars: Option<HashMap<String, Value>>,
+    /// Time used for `sys` time variables instead of the wall clock (optional)
+    pub eval_time: Option<DateTime<Utc>>,
 }
 
 impl ContextInput {
@@ -36,6 +39,7 @@ impl ContextInput {
             service: None,
             llm: None,
             vars: None,
+            eval_time: None,
         }
     }
 
@@ -68,6 +72,12 @@ impl ContextInput {
         self.vars = Some(vars);
         self
     }
+
+    /// Builder method to evaluate with a fixed current time
+    pub fn with_eval_time(mut self, eval_time: DateTime<Utc>) -> Self {
+        self.eval_time = Some(eval_time);
+        self
+    }
 }
 
 /// Execution context for running IR programs with flattened namespace architecture
@@ -120,7 +130,7 @@ impl ExecutionContext {
             service: input.service.unwrap_or_default(),
             llm: input.llm.unwrap_or_default(),
             vars: input.vars.unwrap_or_default(),
-            sys: super::system_vars::build_system_vars(),
+            sys: super::system_vars::build_system_vars(input.eval_time.unwrap_or_else(Utc::now)),
             env: super::env_vars::load_environment_vars(),
             result: ExecutionResult::new(),
         })
@@ -175,7 +185,7 @@ impl ExecutionContext {
             service: service_ns,
             llm: llm_ns,
             vars: vars_ns,
-            sys: super::system_vars::build_system_vars(),
+            sys: super::system_vars::build_system_vars(input.eval_time.unwrap_or_else(Utc::now)),
             env: super::env_vars::load_environment_vars(),
             result,
         })
@@ -14,9 +14,11 @@ use std::collections::HashMap;
 /// - Time information (timestamps, date/time components)
 /// - Business context (business hours, weekday/weekend)
 /// - Environment metadata
-pub(super) fn build_system_vars() -> HashMap<String, Value> {
+///
+/// Time variables are derived from `now`, which is the wall clock unless the
+/// request fixed an evaluation time.
+pub(super) fn build_system_vars(now: chrono::DateTime<chrono::Utc>) -> HashMap<String, Value> {
     let mut sys = HashMap::new();
-    let now = chrono::Utc::now();
 
     // Request identification
     sys.insert(
@@ -16,9 +16,9 @@ use corint_runtime::{
     ApiConfig, ConditionTrace, DecisionResult, ExecutionTrace,
     ExternalApiClient, MetricsCollector, PipelineExecutor, PipelineTrace, RuleTrace, RulesetTrace,
 };
-use std::collections::HashMap;
+use std::collections::{HashMap, HashSet};
 use std::path::Path;
-use std::sync::Arc;
+use std::sync::{Arc, RwLock};
 
 pub struct DecisionEngine {
     /// Compiled programs (one per rule/ruleset/pipeline)
@@ -57,6 +57,9 @@ pub struct DecisionEngine {
 
     /// List service (for reload)
     pub(crate) list_service: Option<Arc<corint_runtime::lists::ListService>>,
+
+    /// Rules disabled engine-wide; skipped when their ruleset runs
+    disabled_rules: RwLock<HashSet<String>>,
 }
 
 impl DecisionEngine {
@@ -242,6 +245,7 @@ impl DecisionEngine {
             repository_config: None,
             feature_executor: feature_executor_clone,
             list_service: list_service_clone,
+            disabled_rules: RwLock::new(HashSet::new()),
         })
     }
 
@@ -394,6 +398,13 @@ impl DecisionEngine {
                                             .collect();
 
                                         for rule_id in rule_ids {
+                                            if self.is_rule_disabled(rule_id) {
+                                                tracing::debug!(
+                                                    "Skipping disabled rule: {}",
+                                                    rule_id
+                                                );
+                                                continue;
+                                            }
                                             if let Some(rule_program) = self.rule_map.get(rule_id)
                                             {
                                                 tracing::info!(
@@ -757,6 +768,10 @@ impl DecisionEngine {
 
                                     // Execute each rule and accumulate results
                                     for rule_id in rule_ids {
+                                        if self.is_rule_disabled(rule_id) {
+                                            tracing::debug!("Skipping disabled rule: {}", rule_id);
+                                            continue;
+                                        }
                                         if let Some(rule_program) = self.rule_map.get(rule_id) {
                                             tracing::info!(
                                                 "Executing rule (via ruleset {}): {}",
@@ -1306,6 +1321,48 @@ impl DecisionEngine {
         &self.config
     }
 
+    /// Get the compiled program for a rule
+    pub fn rule_program(&self, rule_id: &str) -> Option<&Program> {
+        self.rule_map.get(rule_id)
+    }
+
+    /// Enable or disable a rule for all subsequent decisions
+    ///
+    /// Returns an error if the engine has no rule with that ID.
+    pub fn set_rule_enabled(&self, rule_id: &str, enabled: bool) -> Result<()> {
+        if !self.rule_map.contains_key(rule_id) {
+            return Err(SdkError::GenericError(format!(
+                "rule not found: {}",
+                rule_id
+            )));
+        }
+        let mut disabled = self
+            .disabled_rules
+            .write()
+            .unwrap_or_else(|e| e.into_inner());
+        if enabled {
+            disabled.remove(rule_id);
+        } else {
+            disabled.insert(rule_id.to_string());
+        }
+        Ok(())
+    }
+
+    /// Get the IDs of the rules disabled with set_rule_enabled
+    pub fn disabled_rules(&self) -> HashSet<String> {
+        self.disabled_rules
+            .read()
+            .unwrap_or_else(|e| e.into_inner())
+            .clone()
+    }
+
+    fn is_rule_disabled(&self, rule_id: &str) -> bool {
+        self.disabled_rules
+            .read()
+            .unwrap_or_else(|e| e.into_inner())
+            .contains(rule_id)
+    }
+
     /// Reload rules and configurations from repository
     ///
     /// This method reloads all content from the configured repository and recompiles
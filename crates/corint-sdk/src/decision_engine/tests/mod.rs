@@ -546,3 +546,141 @@ ruleset:
     );
     assert!(matches!(response.result.signal, Some(Signal::Decline)));
 }
+
+#[tokio::test]
+async fn test_decide_with_eval_time() {
+    use crate::builder::DecisionEngineBuilder;
+    use chrono::{TimeZone, Utc};
+    use corint_core::ast::Signal;
+
+    // Night-time logins are declined; the evaluation time decides which rule fires
+    let yaml_content = r#"
+pipeline:
+  id: test_pipeline
+  name: Test Pipeline
+  when:
+    event.type: test
+  steps:
+  - include:
+      ruleset: night_ruleset
+
+---
+
+rule:
+  id: night_login
+  name: Night Login
+  when:
+    conditions:
+    - sys.hour >= 22
+  score: 100
+
+---
+
+ruleset:
+  id: night_ruleset
+  rules:
+  - night_login
+  conclusion:
+  - when: total_score >= 100
+    signal: decline
+  - default: true
+    signal: approve
+"#;
+    let temp_file = "/tmp/test_decide_with_eval_time.yaml";
+    std::fs::write(temp_file, yaml_content).unwrap();
+
+    let engine = DecisionEngineBuilder::new()
+        .add_rule_file(temp_file)
+        .build()
+        .await
+        .unwrap();
+
+    let decide_at = |hour: u32| {
+        let mut event_data = HashMap::new();
+        event_data.insert("type".to_string(), Value::String("test".to_string()));
+        let mut request = DecisionRequest::new(event_data);
+        request.options.eval_time = Some(Utc.with_ymd_and_hms(2024, 3, 1, hour, 30, 0).unwrap());
+        request
+    };
+
+    let night = engine.decide(decide_at(23)).await.unwrap();
+    assert!(matches!(night.result.signal, Some(Signal::Decline)));
+    assert!(night
+        .result
+        .triggered_rules
+        .contains(&"night_login".to_string()));
+
+    let day = engine.decide(decide_at(10)).await.unwrap();
+    assert!(matches!(day.result.signal, Some(Signal::Approve)));
+    assert!(day.result.triggered_rules.is_empty());
+}
+
+#[tokio::test]
+async fn test_set_rule_enabled_disables_rule_engine_wide() {
+    use crate::builder::DecisionEngineBuilder;
+    use corint_core::ast::Signal;
+
+    let yaml_content = r#"
+pipeline:
+  id: test_pipeline
+  name: Test Pipeline
+  when:
+    event.type: test
+  steps:
+  - include:
+      ruleset: amount_ruleset
+
+---
+
+rule:
+  id: high_amount
+  name: High Amount
+  when:
+    conditions:
+    - event.amount > 100
+  score: 100
+
+---
+
+ruleset:
+  id: amount_ruleset
+  rules:
+  - high_amount
+  conclusion:
+  - when: total_score >= 100
+    signal: decline
+  - default: true
+    signal: approve
+"#;
+    let temp_file = "/tmp/test_set_rule_enabled.yaml";
+    std::fs::write(temp_file, yaml_content).unwrap();
+
+    let engine = DecisionEngineBuilder::new()
+        .add_rule_file(temp_file)
+        .build()
+        .await
+        .unwrap();
+
+    let high_amount = || {
+        let mut event_data = HashMap::new();
+        event_data.insert("type".to_string(), Value::String("test".to_string()));
+        event_data.insert("amount".to_string(), Value::Number(500.0));
+        DecisionRequest::new(event_data)
+    };
+
+    let enabled = engine.decide(high_amount()).await.unwrap();
+    assert!(matches!(enabled.result.signal, Some(Signal::Decline)));
+
+    engine.set_rule_enabled("high_amount", false).unwrap();
+    assert!(engine.disabled_rules().contains("high_amount"));
+    let disabled = engine.decide(high_amount()).await.unwrap();
+    assert!(matches!(disabled.result.signal, Some(Signal::Approve)));
+    assert!(disabled.result.triggered_rules.is_empty());
+
+    engine.set_rule_enabled("high_amount", true).unwrap();
+    assert!(engine.disabled_rules().is_empty());
+    let reenabled = engine.decide(high_amount()).await.unwrap();
+    assert!(matches!(reenabled.result.signal, Some(Signal::Decline)));
+
+    assert!(engine.set_rule_enabled("missing", false).is_err());
+}
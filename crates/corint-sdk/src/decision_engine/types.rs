@@ -1,5 +1,6 @@
 //! Request/Response types for DecisionEngine
 
+use chrono::{DateTime, Utc};
 use corint_core::Value;
 use corint_runtime::{ContextInput, DecisionResult, ExecutionTrace};
 use serde::{Deserialize, Serialize};
@@ -11,6 +12,10 @@ pub struct DecisionOptions {
     /// Enable detailed execution tracing
     #[serde(default)]
     pub enable_trace: bool,
+
+    /// Evaluate as if the current time were this instant instead of the wall clock
+    #[serde(default)]
+    pub eval_time: Option<DateTime<Utc>>,
 }
 
 /// Decision request (supports Phase 5 multi-namespace format)
@@ -124,6 +129,9 @@ impl DecisionRequest {
         if let Some(vars) = &self.vars {
             input = input.with_vars(vars.clone());
         }
+        if let Some(eval_time) = self.options.eval_time {
+            input = input.with_eval_time(eval_time);
+        }
 
         input
     }